@@ -0,0 +1,181 @@
+package gocodec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	tagTypeBCD  = "bcd"
+	tagTypeTBCD = "tbcd"
+)
+
+var (
+	// ErrInvalidBCD is returned for nibbles or digits which are not valid
+	// in BCD or TBCD.
+	ErrInvalidBCD = errors.New("binstruct: invalid BCD")
+)
+
+const (
+	bcdFiller = 0x0F
+
+	// tbcdDigits are the TBCD digits for the nibbles 0x0 to 0xE (3GPP TS 29.002).
+	tbcdDigits = "0123456789*#abc"
+)
+
+// DecodeBCD decodes packed BCD with the high nibble first. Trailing 0xF
+// filler nibbles are dropped, any other nibble above 9 is an error.
+func DecodeBCD(b []byte) (string, error) {
+	return decodeNibbles(b, false, "0123456789")
+}
+
+// EncodeBCD packs the decimal digits into n bytes of BCD with the high
+// nibble first, filling unused trailing nibbles with 0xF.
+func EncodeBCD(digits string, n int) ([]byte, error) {
+	return encodeNibbles(digits, n, false, "0123456789")
+}
+
+// DecodeTBCD decodes telephony BCD (IMSI, MSISDN) with the low nibble
+// first. The nibbles 0xA to 0xE decode to "*#abc", trailing 0xF filler
+// nibbles are dropped.
+func DecodeTBCD(b []byte) (string, error) {
+	return decodeNibbles(b, true, tbcdDigits)
+}
+
+// EncodeTBCD packs digits from "0123456789*#abc" into n bytes of TBCD with
+// the low nibble first, filling unused trailing nibbles with 0xF.
+func EncodeTBCD(digits string, n int) ([]byte, error) {
+	return encodeNibbles(digits, n, true, tbcdDigits)
+}
+
+// EncodeBCDUint encodes v as 2*n BCD digits, padded with leading zeros.
+func EncodeBCDUint(v uint64, n int) ([]byte, error) {
+	digits := strconv.FormatUint(v, 10)
+	if len(digits) > 2*n {
+		return nil, fmt.Errorf("%w: %d doesn't fit in %d bytes", ErrInvalidBCD, v, n)
+	}
+
+	return EncodeBCD(strings.Repeat("0", 2*n-len(digits))+digits, n)
+}
+
+func decodeNibbles(b []byte, lowFirst bool, alphabet string) (string, error) {
+	var sb strings.Builder
+	sb.Grow(2 * len(b))
+
+	filler := false
+	for i := 0; i < 2*len(b); i++ {
+		nibble := b[i/2] >> 4
+		if (i%2 == 1) != lowFirst {
+			nibble = b[i/2] & 0x0F
+		}
+
+		switch {
+		case nibble == bcdFiller:
+			filler = true
+		case filler:
+			return "", fmt.Errorf("%w: digit after filler in byte %d", ErrInvalidBCD, i/2)
+		case int(nibble) >= len(alphabet):
+			return "", fmt.Errorf("%w: nibble 0x%X in byte %d", ErrInvalidBCD, nibble, i/2)
+		default:
+			sb.WriteByte(alphabet[nibble])
+		}
+	}
+
+	return sb.String(), nil
+}
+
+func encodeNibbles(digits string, n int, lowFirst bool, alphabet string) ([]byte, error) {
+	if len(digits) > 2*n {
+		return nil, fmt.Errorf("%w: %d digits don't fit in %d bytes", ErrInvalidBCD, len(digits), n)
+	}
+
+	b := make([]byte, n)
+	for i := 0; i < 2*n; i++ {
+		nibble := byte(bcdFiller)
+		if i < len(digits) {
+			idx := strings.IndexByte(alphabet, digits[i])
+			if idx < 0 {
+				return nil, fmt.Errorf("%w: digit %q", ErrInvalidBCD, digits[i])
+			}
+			nibble = byte(idx)
+		}
+
+		if (i%2 == 1) != lowFirst {
+			b[i/2] |= nibble
+		} else {
+			b[i/2] |= nibble << 4
+		}
+	}
+
+	return b, nil
+}
+
+func readBCD(r Reader, format string, n int) (string, error) {
+	if br, ok := r.(BCDReader); ok {
+		if format == tagTypeTBCD {
+			return br.ReadTBCD(n)
+		}
+		return br.ReadBCD(n)
+	}
+
+	_, b, err := r.ReadBytes(n)
+	if err != nil {
+		return "", err
+	}
+	if format == tagTypeTBCD {
+		return DecodeTBCD(b)
+	}
+	return DecodeBCD(b)
+}
+
+func decodeBCDField(r Reader, fieldValue reflect.Value, fieldData *fieldReadData) error {
+	if fieldData.Length == nil {
+		return errors.New("need set tag with len for " + fieldData.BCD)
+	}
+
+	digits, err := readBCD(r, fieldData.BCD, int(*fieldData.Length))
+	if err != nil {
+		return err
+	}
+
+	switch fieldValue.Kind() {
+	case reflect.String:
+		if fieldValue.CanSet() {
+			fieldValue.SetString(digits)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var v uint64
+		if digits != "" {
+			v, err = strconv.ParseUint(digits, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w: %q is not a number", ErrInvalidBCD, digits)
+			}
+		}
+
+		if fieldValue.Kind() >= reflect.Uint {
+			if fieldValue.OverflowUint(v) {
+				return fmt.Errorf("%w: %d overflows %s", ErrInvalidBCD, v, fieldValue.Type())
+			}
+			if fieldValue.CanSet() {
+				fieldValue.SetUint(v)
+			}
+		} else {
+			if v > 1<<63-1 || fieldValue.OverflowInt(int64(v)) {
+				return fmt.Errorf("%w: %d overflows %s", ErrInvalidBCD, v, fieldValue.Type())
+			}
+			if fieldValue.CanSet() {
+				fieldValue.SetInt(int64(v))
+			}
+		}
+
+	default:
+		return errors.New(fieldData.BCD + " tag needs an integer or string field")
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package gocodec
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BCDTags(t *testing.T) {
+	data := []byte{
+		0x00, 0x12, 0x34, // bcd 1234
+		0x20, 0x26, 0x10, 0x18, // bcd date
+		0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0xF5, // tbcd IMSI 123456789012345
+	}
+
+	var v struct {
+		Meter uint32 `bin:"bcd,len:3"`
+		Date  string `bin:"bcd,len:4"`
+		IMSI  string `bin:"tbcd,len:8"`
+	}
+
+	err := UnmarshalBE(data, &v)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1234), v.Meter)
+	require.Equal(t, "20261018", v.Date)
+	require.Equal(t, "123456789012345", v.IMSI)
+}
+
+func Test_BCDErrors(t *testing.T) {
+	var v struct {
+		N int8 `bin:"bcd,len:2"`
+	}
+	err := UnmarshalBE([]byte{0x1A, 0x00}, &v)
+	require.ErrorIs(t, err, ErrInvalidBCD)
+
+	err = UnmarshalBE([]byte{0x1F, 0x20}, &v)
+	require.ErrorIs(t, err, ErrInvalidBCD)
+
+	err = UnmarshalBE([]byte{0x02, 0x00}, &v)
+	require.ErrorIs(t, err, ErrInvalidBCD)
+
+	var f struct {
+		F float32 `bin:"bcd,len:2"`
+	}
+	err = UnmarshalBE([]byte{0x00, 0x00}, &f)
+	require.Error(t, err)
+}
+
+func Test_BCDEncode(t *testing.T) {
+	b, err := EncodeBCD("123", 2)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x12, 0x3F}, b)
+
+	b, err = EncodeBCDUint(1234, 3)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x00, 0x12, 0x34}, b)
+
+	b, err = EncodeTBCD("12345*#", 4)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x21, 0x43, 0xA5, 0xFB}, b)
+
+	s, err := DecodeTBCD(b)
+	require.NoError(t, err)
+	require.Equal(t, "12345*#", s)
+
+	_, err = EncodeBCD("12*", 2)
+	require.ErrorIs(t, err, ErrInvalidBCD)
+
+	_, err = EncodeBCDUint(123456, 2)
+	require.ErrorIs(t, err, ErrInvalidBCD)
+
+	var buf Buffer
+	_, err = buf.WriteBCD("0415", 2)
+	require.NoError(t, err)
+	_, err = buf.WriteTBCD("4915", 2)
+	require.NoError(t, err)
+
+	s, err = buf.ReadBCD(2)
+	require.NoError(t, err)
+	require.Equal(t, "0415", s)
+
+	s, err = buf.ReadTBCD(2)
+	require.NoError(t, err)
+	require.Equal(t, "4915", s)
+}
+
+func Test_BCDReaderFallback(t *testing.T) {
+	var _ BCDReader = NewReaderFromBytes(nil, binary.BigEndian, false).(BCDReader)
+
+	r := readerOnly{NewReaderFromBytes([]byte{0x12, 0x34, 0x21, 0xF3}, binary.BigEndian, false)}
+	_, ok := Reader(r).(BCDReader)
+	require.False(t, ok)
+
+	s, err := readBCD(r, tagTypeBCD, 2)
+	require.NoError(t, err)
+	require.Equal(t, "1234", s)
+
+	s, err = readBCD(r, tagTypeTBCD, 2)
+	require.NoError(t, err)
+	require.Equal(t, "123", s)
+}
@@ -142,6 +142,38 @@ func PutUint56BE(b []byte, v uint64) {
 	b[6] = byte(v)
 }
 
+func GetInt24LE(b []byte) int32 {
+	return int32(GetUint24LE(b)<<8) >> 8
+}
+
+func GetInt24BE(b []byte) int32 {
+	return int32(GetUint24BE(b)<<8) >> 8
+}
+
+func GetInt40LE(b []byte) int64 {
+	return int64(GetUint40LE(b)<<24) >> 24
+}
+
+func GetInt40BE(b []byte) int64 {
+	return int64(GetUint40BE(b)<<24) >> 24
+}
+
+func GetInt48LE(b []byte) int64 {
+	return int64(GetUint48LE(b)<<16) >> 16
+}
+
+func GetInt48BE(b []byte) int64 {
+	return int64(GetUint48BE(b)<<16) >> 16
+}
+
+func GetInt56LE(b []byte) int64 {
+	return int64(GetUint56LE(b)<<8) >> 8
+}
+
+func GetInt56BE(b []byte) int64 {
+	return int64(GetUint56BE(b)<<8) >> 8
+}
+
 func GetUint64LE(b []byte) uint64 {
 	return binary.LittleEndian.Uint64(b)
 }
@@ -158,6 +190,38 @@ func PutUint64BE(b []byte, v uint64) {
 	binary.BigEndian.PutUint64(b, v)
 }
 
+func GetFloat16BE(b []byte) float32 {
+	return Float16frombits(GetUint16BE(b))
+}
+
+func PutFloat16BE(b []byte, v float32) {
+	PutUint16BE(b, Float16bits(v))
+}
+
+func GetFloat16LE(b []byte) float32 {
+	return Float16frombits(GetUint16LE(b))
+}
+
+func PutFloat16LE(b []byte, v float32) {
+	PutUint16LE(b, Float16bits(v))
+}
+
+func GetBFloat16BE(b []byte) float32 {
+	return BFloat16frombits(GetUint16BE(b))
+}
+
+func PutBFloat16BE(b []byte, v float32) {
+	PutUint16BE(b, BFloat16bits(v))
+}
+
+func GetBFloat16LE(b []byte) float32 {
+	return BFloat16frombits(GetUint16LE(b))
+}
+
+func PutBFloat16LE(b []byte, v float32) {
+	PutUint16LE(b, BFloat16bits(v))
+}
+
 func GetFloat32BE(b []byte) float32 {
 	return math.Float32frombits(GetUint32BE(b))
 }
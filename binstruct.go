@@ -2,51 +2,130 @@ package gocodec
 
 import (
 	"encoding/binary"
+	"errors"
 	"io"
 )
 
 // UnmarshalLE parses the binary data with little-endian byte order and
 // stores the result in the value pointed to by v.
-func UnmarshalLE(data []byte, v interface{}) error {
-	return Unmarshal(data, binary.LittleEndian, v)
+func UnmarshalLE(data []byte, v interface{}, opts ...Option) error {
+	return Unmarshal(data, binary.LittleEndian, v, opts...)
 }
 
 // UnmarshalBE parses the binary data with big-endian byte order and
 // stores the result in the value pointed to by v.
-func UnmarshalBE(data []byte, v interface{}) error {
-	return Unmarshal(data, binary.BigEndian, v)
+func UnmarshalBE(data []byte, v interface{}, opts ...Option) error {
+	return Unmarshal(data, binary.BigEndian, v, opts...)
 }
 
 // Unmarshal parses the binary data with byte order and stores the result
 // in the value pointed to by v. If v is nil or not a pointer,
 // Unmarshal returns an InvalidUnmarshalError.
-func Unmarshal(data []byte, order binary.ByteOrder, v interface{}) error {
-	return NewReaderFromBytes(data, order, false).Unmarshal(v)
+func Unmarshal(data []byte, order binary.ByteOrder, v interface{}, opts ...Option) error {
+	return NewReaderFromBytes(data, order, false, opts...).Unmarshal(v)
 }
 
 // A Decoder reads and decodes binary values from an input stream.
 type Decoder struct {
-	r     io.ReadSeeker
-	order binary.ByteOrder
-	debug bool
+	r      io.ReadSeeker
+	order  binary.ByteOrder
+	debug  bool
+	opts   []Option
+	tracer Tracer
+
+	recordOffset int64
 }
 
 // NewDecoder returns a new decoder that reads from r with byte order.
-func NewDecoder(r io.ReadSeeker, order binary.ByteOrder) *Decoder {
+// The options are applied to every Decode call.
+func NewDecoder(r io.ReadSeeker, order binary.ByteOrder, opts ...Option) *Decoder {
 	return &Decoder{
 		r:     r,
 		order: order,
 		debug: false,
+		opts:  opts,
 	}
 }
 
 // SetDebug if set true, all read bytes and offsets will be displayed.
+// It has no effect when a tracer is set.
 func (dec *Decoder) SetDebug(debug bool) {
 	dec.debug = debug
 }
 
+// SetTracer sets the tracer notified about reads, seeks and fields.
+// It takes precedence over a tracer given as option to NewDecoder.
+func (dec *Decoder) SetTracer(t Tracer) {
+	dec.tracer = t
+}
+
 // Decode reads the binary-encoded value from its
 // input and stores it in the value pointed to by v.
+//
+// If the input ends before the first byte of the value, Decode returns
+// io.EOF. If it ends inside the value, the returned error matches
+// io.ErrUnexpectedEOF instead.
 func (dec *Decoder) Decode(v interface{}) error {
-	return NewReader(dec.r, dec.order, dec.debug).Unmarshal(v)
+	return dec.decode(dec.reader(), v)
+}
+
+func (dec *Decoder) decode(r Reader, v interface{}) error {
+	start := offset(r)
+	dec.recordOffset = start
+
+	err := r.Unmarshal(v)
+	if err != nil && errors.Is(err, io.EOF) {
+		if offset(r) == start {
+			return io.EOF
+		}
+
+		return unexpectedEOF(err)
+	}
+
+	return err
+}
+
+// More reports whether there is at least one more byte to decode.
+func (dec *Decoder) More() bool {
+	_, err := dec.reader().Peek(1)
+	return err == nil
+}
+
+// RecordOffset returns the input offset where the value of the
+// last Decode call started.
+func (dec *Decoder) RecordOffset() int64 {
+	return dec.recordOffset
+}
+
+// DecodeAll decodes back-to-back values until the end of the input.
+// For every record newValue must return a pointer to decode into, then
+// fn is called with it; RecordOffset reports its start offset inside fn.
+// DecodeAll returns nil when the input ends at a record boundary,
+// an error matching io.ErrUnexpectedEOF for a truncated trailing record,
+// or the first error returned by fn.
+func (dec *Decoder) DecodeAll(newValue func() interface{}, fn func(v interface{}) error) error {
+	for {
+		v := newValue()
+
+		err := dec.Decode(v)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = fn(v); err != nil {
+			return err
+		}
+	}
+}
+
+func (dec *Decoder) reader(extra ...Option) Reader {
+	opts := append(dec.opts[:len(dec.opts):len(dec.opts)], extra...)
+	if dec.tracer != nil {
+		opts = append(opts, WithTracer(dec.tracer))
+	}
+
+	return NewReader(dec.r, dec.order, dec.debug, opts...)
 }
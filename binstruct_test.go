@@ -1,7 +1,10 @@
 package gocodec
 
 import (
+	"bytes"
 	"encoding/binary"
+	"io"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -933,3 +936,169 @@ func Test_OffsetRestore(t *testing.T) {
 	require.Equal(t, []byte{0x04, 0x05}, v.Data)
 	require.Equal(t, []byte{0x01, 0x02, 0x03}, v.Other)
 }
+
+func Test_DecodeError(t *testing.T) {
+	type item struct {
+		ID   uint8
+		Name string `bin:"len:2"`
+	}
+
+	type header struct {
+		Count uint8
+		Items []item `bin:"len:Count"`
+	}
+
+	var v struct {
+		Header header
+	}
+
+	data := []byte{0x02, 0x01, 'a', 'b', 0x02, 'c'}
+	err := UnmarshalBE(data, &v)
+	require.Error(t, err)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "Header.Items[1].Name", decodeErr.Path)
+	require.Equal(t, int64(5), decodeErr.Offset)
+	require.Equal(t, reflect.TypeOf(""), decodeErr.Type)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.EqualError(t, err, `failed set value to field "Header.Items[1].Name": unexpected EOF`)
+}
+
+func Test_DecodeErrorTag(t *testing.T) {
+	var v struct {
+		A      uint8
+		Nested struct {
+			Data []byte `bin:"len:Missing"`
+		}
+	}
+
+	err := UnmarshalBE([]byte{0x01, 0x02}, &v)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "Nested.Data", decodeErr.Path)
+	require.Equal(t, int64(1), decodeErr.Offset)
+	require.Equal(t, reflect.TypeOf([]byte{}), decodeErr.Type)
+}
+
+func Test_Layout(t *testing.T) {
+	type item struct {
+		ID   uint8
+		Name string `bin:"len:2"`
+	}
+
+	var v struct {
+		Magic uint16
+		Count uint8
+		Items []item `bin:"len:Count"`
+		Skip  uint8  `bin:"-"`
+	}
+
+	data := []byte{0xCA, 0xFE, 0x02, 0x01, 'a', 'b', 0x02, 'c', 'd'}
+
+	var layout FieldLayout
+	err := UnmarshalBE(data, &v, WithLayout(&layout))
+	require.NoError(t, err)
+
+	require.Equal(t, int64(0), layout.Offset)
+	require.Equal(t, int64(len(data)), layout.Length)
+	require.Len(t, layout.Children, 3)
+
+	items := layout.Children[2]
+	require.Equal(t, "Items", items.Path)
+	require.Equal(t, int64(3), items.Offset)
+	require.Equal(t, int64(6), items.Length)
+	require.Len(t, items.Children, 2)
+
+	name := items.Children[1].Children[1]
+	require.Equal(t, "Items[1].Name", name.Path)
+	require.Equal(t, int64(7), name.Offset)
+	require.Equal(t, int64(2), name.Length)
+	require.Equal(t, "cd", name.Value)
+
+	require.Equal(t, name, layout.Find(8))
+	require.Equal(t, "Magic", layout.Find(1).Path)
+	require.Nil(t, layout.Find(int64(len(data))))
+}
+
+type layoutCustomStruct struct {
+	Inner struct {
+		A uint8
+		B uint8
+	} `bin:"ReadInner"`
+}
+
+func (s *layoutCustomStruct) ReadInner(r Reader) error {
+	return r.Unmarshal(&s.Inner)
+}
+
+func Test_LayoutCustomFunc(t *testing.T) {
+	var v layoutCustomStruct
+	var layout FieldLayout
+
+	err := NewDecoder(bytes.NewReader([]byte{0x01, 0x02}), binary.BigEndian, WithLayout(&layout)).Decode(&v)
+	require.NoError(t, err)
+
+	require.Len(t, layout.Children, 1)
+	inner := layout.Children[0]
+	require.Equal(t, "Inner", inner.Path)
+	require.Equal(t, int64(2), inner.Length)
+	require.Len(t, inner.Children, 2)
+	require.Equal(t, "Inner.B", inner.Children[1].Path)
+	require.Equal(t, uint8(2), inner.Children[1].Value)
+}
+
+func Test_DecoderRecords(t *testing.T) {
+	type record struct {
+		ID   uint8
+		Size uint16
+	}
+
+	data := []byte{0x01, 0x00, 0x10, 0x02, 0x00, 0x20}
+	dec := NewDecoder(bytes.NewReader(data), binary.BigEndian)
+
+	var got []record
+	var offsets []int64
+	err := dec.DecodeAll(func() interface{} { return new(record) }, func(v interface{}) error {
+		got = append(got, *v.(*record))
+		offsets = append(offsets, dec.RecordOffset())
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []record{{1, 0x10}, {2, 0x20}}, got)
+	require.Equal(t, []int64{0, 3}, offsets)
+	require.False(t, dec.More())
+
+	var r record
+	require.Equal(t, io.EOF, dec.Decode(&r))
+}
+
+func Test_DecoderTruncatedRecord(t *testing.T) {
+	type record struct {
+		ID   uint8
+		Size uint16
+	}
+
+	data := []byte{0x01, 0x00, 0x10, 0x02}
+	dec := NewDecoder(bytes.NewReader(data), binary.BigEndian)
+
+	var r record
+	require.NoError(t, dec.Decode(&r))
+	require.True(t, dec.More())
+
+	err := dec.Decode(&r)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.NotErrorIs(t, err, io.EOF)
+	require.Equal(t, int64(3), dec.RecordOffset())
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "Size", decodeErr.Path)
+
+	err = NewDecoder(bytes.NewReader(data), binary.BigEndian).DecodeAll(
+		func() interface{} { return new(record) },
+		func(v interface{}) error { return nil },
+	)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,159 @@
+package gocodec
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrBitCount is returned when more than 64 or a negative number of bits
+// is requested at once.
+var ErrBitCount = errors.New("binstruct: bit count out of range")
+
+// BitOrder is the order in which bits are taken from each byte.
+type BitOrder int
+
+const (
+	// MSBFirst takes the most significant bit of each byte first, and the
+	// first bit read is the most significant bit of the value (H.264, AAC,
+	// CAN Motorola signals).
+	MSBFirst BitOrder = iota
+	// LSBFirst takes the least significant bit of each byte first, and the
+	// first bit read is the least significant bit of the value (DEFLATE,
+	// CAN Intel signals).
+	LSBFirst
+)
+
+// BitReader reads a byte stream bit by bit. Bytes are taken from the
+// underlying source only when their first bit is needed, so a BitReader
+// created inside a custom func tag method leaves the Reader positioned
+// after the last partially read byte.
+type BitReader struct {
+	src       io.ByteReader
+	remaining func() (int64, bool)
+	order     BitOrder
+	cur       byte
+	nbits     int // unread bits left in cur
+}
+
+// NewBitReader returns a BitReader reading from r.
+func NewBitReader(r Reader, order BitOrder) *BitReader {
+	return &BitReader{
+		src:       r,
+		remaining: func() (int64, bool) { return remaining(r) },
+		order:     order,
+	}
+}
+
+// NewBitReaderFromCursor returns a BitReader reading from c. The cursor
+// advances as bytes are consumed.
+func NewBitReaderFromCursor(c *Cursor[byte], order BitOrder) *BitReader {
+	return &BitReader{
+		src:       cursorByteReader{c},
+		remaining: func() (int64, bool) { return c.Len(), true },
+		order:     order,
+	}
+}
+
+// ReadBit reads a single bit and returns it as 0 or 1.
+func (br *BitReader) ReadBit() (uint8, error) {
+	if br.nbits == 0 {
+		b, err := br.src.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		br.cur = b
+		br.nbits = 8
+	}
+
+	br.nbits--
+	if br.order == LSBFirst {
+		return (br.cur >> (7 - br.nbits)) & 1, nil
+	}
+	return (br.cur >> br.nbits) & 1, nil
+}
+
+// ReadBool reads a single bit and returns true if it is set.
+func (br *BitReader) ReadBool() (bool, error) {
+	bit, err := br.ReadBit()
+	return bit == 1, err
+}
+
+// ReadBits reads n bits, 0 to 64, and returns them as an unsigned value.
+// It returns io.EOF if no bit could be read and io.ErrUnexpectedEOF if
+// the source ends in the middle of the value.
+func (br *BitReader) ReadBits(n int) (uint64, error) {
+	if n < 0 || n > 64 {
+		return 0, ErrBitCount
+	}
+
+	var v uint64
+	for i := 0; i < n; i++ {
+		bit, err := br.ReadBit()
+		if err != nil {
+			return 0, eofInValue(err, i > 0)
+		}
+
+		if br.order == LSBFirst {
+			v |= uint64(bit) << i
+		} else {
+			v = v<<1 | uint64(bit)
+		}
+	}
+
+	return v, nil
+}
+
+// ReadSignedBits reads n bits and sign-extends them from bit n-1.
+func (br *BitReader) ReadSignedBits(n int) (int64, error) {
+	v, err := br.ReadBits(n)
+	if err != nil || n == 0 {
+		return 0, err
+	}
+
+	shift := 64 - n
+	return int64(v<<shift) >> shift, nil
+}
+
+// Align discards the unread bits of the current byte, so the next read
+// starts on a byte boundary. It returns the number of bits discarded.
+func (br *BitReader) Align() int {
+	n := br.nbits
+	br.nbits = 0
+	return n
+}
+
+// Aligned reports whether the reader is on a byte boundary.
+func (br *BitReader) Aligned() bool {
+	return br.nbits == 0
+}
+
+// BitsRemaining returns the number of bits left to read, or -1 if the
+// size of the underlying source is unknown.
+func (br *BitReader) BitsRemaining() int64 {
+	n, ok := br.remaining()
+	if !ok {
+		return -1
+	}
+	return n*8 + int64(br.nbits)
+}
+
+// eofInValue turns io.EOF into io.ErrUnexpectedEOF if part of the value
+// was already read.
+func eofInValue(err error, started bool) error {
+	if started && errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// cursorByteReader adapts a Cursor to io.ByteReader with io.EOF at the end.
+type cursorByteReader struct {
+	c *Cursor[byte]
+}
+
+func (r cursorByteReader) ReadByte() (byte, error) {
+	if r.c.EOF() {
+		return 0, io.EOF
+	}
+	return r.c.Read()
+}
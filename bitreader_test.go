@@ -0,0 +1,107 @@
+package gocodec
+
+import (
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BitReaderMSBFirst(t *testing.T) {
+	// AAC ADTS header: syncword 0xFFF, id 0, layer 00, protection absent 1,
+	// profile 01, sampling index 0100, private 0, channels 010
+	c := NewCursor([]byte{0xFF, 0xF1, 0x50, 0x80})
+	br := NewBitReaderFromCursor(&c, MSBFirst)
+	require.Equal(t, int64(32), br.BitsRemaining())
+
+	sync, err := br.ReadBits(12)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0xFFF), sync)
+
+	id, err := br.ReadBit()
+	require.NoError(t, err)
+	require.Equal(t, uint8(0), id)
+
+	layer, err := br.ReadBits(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), layer)
+
+	absent, err := br.ReadBool()
+	require.NoError(t, err)
+	require.True(t, absent)
+
+	profile, err := br.ReadBits(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), profile)
+
+	freq, err := br.ReadBits(4)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), freq)
+
+	_, err = br.ReadBit()
+	require.NoError(t, err)
+
+	channels, err := br.ReadBits(3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), channels)
+	require.Equal(t, int64(6), br.BitsRemaining())
+
+	require.Equal(t, 6, br.Align())
+	require.True(t, br.Aligned())
+	require.Equal(t, int64(0), br.BitsRemaining())
+
+	_, err = br.ReadBit()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func Test_BitReaderLSBFirst(t *testing.T) {
+	// CAN Intel signal: 12 bits 0xABC starting at bit 4, then a signed 4 bit -3
+	c := NewCursor([]byte{0xC5, 0xAB, 0x0D})
+	br := NewBitReaderFromCursor(&c, LSBFirst)
+
+	low, err := br.ReadBits(4)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), low)
+
+	sig, err := br.ReadBits(12)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0xABC), sig)
+
+	s, err := br.ReadSignedBits(4)
+	require.NoError(t, err)
+	require.Equal(t, int64(-3), s)
+
+	_, err = br.ReadBits(8)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	_, err = br.ReadBits(65)
+	require.ErrorIs(t, err, ErrBitCount)
+}
+
+type bitFlags struct {
+	Marker uint8
+	Flags  [3]uint8 `bin:"ReadFlags"`
+	Tail   uint8
+}
+
+func (f *bitFlags) ReadFlags(r Reader) error {
+	br := NewBitReader(r, MSBFirst)
+	for i := range f.Flags {
+		v, err := br.ReadBits(3)
+		if err != nil {
+			return err
+		}
+		f.Flags[i] = uint8(v)
+	}
+	return nil
+}
+
+func Test_BitReaderFromFuncTag(t *testing.T) {
+	var v bitFlags
+	err := Unmarshal([]byte{0x7E, 0b101_011_00, 0b1_0000000, 0x42}, binary.BigEndian, &v)
+	require.NoError(t, err)
+	require.Equal(t, uint8(0x7E), v.Marker)
+	require.Equal(t, [3]uint8{5, 3, 1}, v.Flags)
+	require.Equal(t, uint8(0x42), v.Tail)
+}
@@ -0,0 +1,140 @@
+package gocodec
+
+import (
+	"io"
+)
+
+// BitWriter builds a byte stream bit by bit, the counterpart of BitReader.
+// Completed bytes are kept in memory until Flush writes them to the
+// destination, if one was given.
+type BitWriter struct {
+	w     io.Writer
+	order BitOrder
+	buf   []byte // completed bytes not yet flushed
+	cur   byte
+	nbits int // bits used in cur
+}
+
+// NewBitWriter returns a BitWriter which collects the output in memory.
+func NewBitWriter(order BitOrder) *BitWriter {
+	return &BitWriter{order: order}
+}
+
+// NewBitWriterTo returns a BitWriter which writes completed bytes to w on
+// Flush. A *Buffer can be used as w.
+func NewBitWriterTo(w io.Writer, order BitOrder) *BitWriter {
+	return &BitWriter{w: w, order: order}
+}
+
+// WriteBit writes the lowest bit of bit.
+func (bw *BitWriter) WriteBit(bit uint8) error {
+	bit &= 1
+	if bw.order == LSBFirst {
+		bw.cur |= bit << bw.nbits
+	} else {
+		bw.cur |= bit << (7 - bw.nbits)
+	}
+
+	bw.nbits++
+	if bw.nbits == 8 {
+		bw.buf = append(bw.buf, bw.cur)
+		bw.cur = 0
+		bw.nbits = 0
+	}
+
+	return nil
+}
+
+// WriteBool writes one bit, set if v is true.
+func (bw *BitWriter) WriteBool(v bool) error {
+	if v {
+		return bw.WriteBit(1)
+	}
+	return bw.WriteBit(0)
+}
+
+// WriteBits writes the lowest n bits of v, 0 to 64, in the writer's bit
+// order.
+func (bw *BitWriter) WriteBits(v uint64, n int) error {
+	if n < 0 || n > 64 {
+		return ErrBitCount
+	}
+
+	for i := 0; i < n; i++ {
+		shift := n - 1 - i
+		if bw.order == LSBFirst {
+			shift = i
+		}
+
+		if err := bw.WriteBit(uint8(v >> shift)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteSignedBits writes v as an n bit two's complement value.
+func (bw *BitWriter) WriteSignedBits(v int64, n int) error {
+	return bw.WriteBits(uint64(v), n)
+}
+
+// AlignZero pads the current byte with zero bits up to the next byte
+// boundary.
+func (bw *BitWriter) AlignZero() error {
+	return bw.align(0)
+}
+
+// AlignOne pads the current byte with one bits up to the next byte
+// boundary.
+func (bw *BitWriter) AlignOne() error {
+	return bw.align(1)
+}
+
+func (bw *BitWriter) align(bit uint8) error {
+	for bw.nbits != 0 {
+		if err := bw.WriteBit(bit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Aligned reports whether the writer is on a byte boundary.
+func (bw *BitWriter) Aligned() bool {
+	return bw.nbits == 0
+}
+
+// Bytes returns the unflushed output. A partially written last byte is
+// included with its unused bits set to zero.
+func (bw *BitWriter) Bytes() []byte {
+	b := make([]byte, len(bw.buf), len(bw.buf)+1)
+	copy(b, bw.buf)
+	if bw.nbits > 0 {
+		b = append(b, bw.cur)
+	}
+	return b
+}
+
+// Flush writes the completed bytes to the destination. A partially written
+// byte stays pending, call AlignZero or AlignOne first to flush it too.
+// Flush does nothing for a BitWriter created with NewBitWriter.
+func (bw *BitWriter) Flush() error {
+	if bw.w == nil || len(bw.buf) == 0 {
+		return nil
+	}
+
+	n, err := bw.w.Write(bw.buf)
+	bw.buf = bw.buf[:copy(bw.buf, bw.buf[n:])]
+	if err == nil && len(bw.buf) > 0 {
+		err = io.ErrShortWrite
+	}
+	return err
+}
+
+// Reset discards all pending output.
+func (bw *BitWriter) Reset() {
+	bw.buf = bw.buf[:0]
+	bw.cur = 0
+	bw.nbits = 0
+}
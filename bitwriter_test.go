@@ -0,0 +1,86 @@
+package gocodec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BitWriter(t *testing.T) {
+	bw := NewBitWriter(MSBFirst)
+	require.NoError(t, bw.WriteBits(0xFFF, 12))
+	require.NoError(t, bw.WriteBit(0))
+	require.NoError(t, bw.WriteBits(0, 2))
+	require.NoError(t, bw.WriteBool(true))
+	require.NoError(t, bw.WriteBits(1, 2))
+	require.NoError(t, bw.WriteBits(4, 4))
+	require.NoError(t, bw.WriteBit(0))
+	require.NoError(t, bw.WriteBits(2, 3))
+	require.False(t, bw.Aligned())
+	require.Equal(t, []byte{0xFF, 0xF1, 0x50, 0x80}, bw.Bytes())
+
+	require.NoError(t, bw.AlignOne())
+	require.Equal(t, []byte{0xFF, 0xF1, 0x50, 0xBF}, bw.Bytes())
+
+	bw = NewBitWriter(LSBFirst)
+	require.NoError(t, bw.WriteBits(5, 4))
+	require.NoError(t, bw.WriteBits(0xABC, 12))
+	require.NoError(t, bw.WriteSignedBits(-3, 4))
+	require.NoError(t, bw.AlignZero())
+	require.Equal(t, []byte{0xC5, 0xAB, 0x0D}, bw.Bytes())
+
+	require.ErrorIs(t, bw.WriteBits(0, 65), ErrBitCount)
+}
+
+func Test_BitWriterFlush(t *testing.T) {
+	var buf Buffer
+	bw := NewBitWriterTo(&buf, MSBFirst)
+	require.NoError(t, bw.WriteBits(0xABC, 12))
+	require.NoError(t, bw.Flush())
+	require.Equal(t, int64(1), buf.Len())
+	require.Equal(t, []byte{0xC0}, bw.Bytes())
+
+	require.NoError(t, bw.AlignZero())
+	require.NoError(t, bw.Flush())
+	require.Empty(t, bw.Bytes())
+
+	b := make([]byte, 2)
+	_, err := buf.Read(b)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xAB, 0xC0}, b)
+
+	var out bytes.Buffer
+	bw = NewBitWriterTo(&out, LSBFirst)
+	require.NoError(t, bw.WriteBits(0x1234, 16))
+	require.NoError(t, bw.Flush())
+	require.Equal(t, []byte{0x34, 0x12}, out.Bytes())
+}
+
+func Test_BitRoundTrip(t *testing.T) {
+	widths := []int{1, 3, 7, 8, 9, 13, 16, 31, 33, 63, 64}
+
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		bw := NewBitWriter(order)
+		values := make([]uint64, len(widths))
+		for i, n := range widths {
+			values[i] = 0xDEADBEEFCAFEF00D >> (64 - n)
+			require.NoError(t, bw.WriteBits(values[i], n))
+		}
+		require.NoError(t, bw.WriteSignedBits(-1000, 12))
+		require.NoError(t, bw.AlignZero())
+
+		c := NewCursor(bw.Bytes())
+		br := NewBitReaderFromCursor(&c, order)
+		for i, n := range widths {
+			v, err := br.ReadBits(n)
+			require.NoError(t, err)
+			require.Equal(t, values[i], v, "order %d width %d", order, n)
+		}
+		s, err := br.ReadSignedBits(12)
+		require.NoError(t, err)
+		require.Equal(t, int64(-1000), s)
+		require.Less(t, br.Align(), 8)
+		require.Equal(t, int64(0), br.BitsRemaining())
+	}
+}
@@ -1,17 +1,108 @@
 package gocodec
 
 import (
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
 )
 
 var ErrBufferFull = errors.New("funny/binary.Buffer: buffer full")
 
+// ErrVarintOverflow is returned for varints longer than binary.MaxVarintLen64
+// bytes or exceeding 64 bits.
+var ErrVarintOverflow = errors.New("binstruct: varint overflows a 64-bit integer")
+
+// ErrSeekOutOfRange is returned by Buffer.Seek for positions outside the
+// buffered data.
+var ErrSeekOutOfRange = errors.New("binstruct: seek out of buffer range")
+
+// BufferError is the first error of a Buffer in sticky error mode.
+type BufferError struct {
+	Offset int64  // read or write position where the operation failed
+	Op     string // name of the failed method
+	Err    error
+}
+
+func (e *BufferError) Error() string {
+	return fmt.Sprintf("binstruct: Buffer.%s at offset %d: %v", e.Op, e.Offset, e.Err)
+}
+
+func (e *BufferError) Unwrap() error {
+	return e.Err
+}
+
 type Buffer struct {
-	cursor Cursor[byte]
+	cursor  Cursor[byte]
+	base    int64 // offset of cursor.buffer[0], advanced by Compact
+	maxSize int
+
+	compactThreshold int
+	marks            []int64 // offsets of active checkpoints, oldest first
+
+	sticky bool
+	err    *BufferError
+
+	runeEnd  int // offset after the last ReadRune, for UnreadRune
+	runeSize int
+}
+
+// NewBufferFromBytes returns a Buffer holding b as its initial unread data.
+// The Buffer takes ownership of b and appends to it while capacity lasts,
+// so passing b[:0] reuses preallocated memory for an empty buffer. Compact
+// moves the data to new memory.
+func NewBufferFromBytes(b []byte) *Buffer {
+	return &Buffer{cursor: NewCursor(b)}
+}
+
+// SetMaxSize limits the buffered data to n bytes, 0 means no limit. Bytes
+// already read count until the buffer is compacted. Writes beyond the
+// limit return ErrBufferFull. It returns buf for chaining.
+func (buf *Buffer) SetMaxSize(n int) *Buffer {
+	buf.maxSize = n
+	return buf
 }
 
+// SetStickyErrors turns on error accumulation: after the first failed read
+// or write, reads return zero values and writes do nothing, all returning
+// that first error, until Reset. A sequence of reads can then be checked
+// once with Error. It returns buf for chaining.
+func (buf *Buffer) SetStickyErrors(sticky bool) *Buffer {
+	buf.sticky = sticky
+	return buf
+}
+
+// Error returns the first error recorded in sticky error mode as a
+// *BufferError, or nil.
 func (buf *Buffer) Error() error {
-	return nil
+	if buf.err == nil {
+		return nil
+	}
+	return buf.err
+}
+
+// fail records err of a read at the read position as the sticky error,
+// if that mode is on and none is set yet. It returns the error the failed
+// operation should return.
+func (buf *Buffer) fail(op string, err error) error {
+	return buf.failAt(op, buf.Offset(), err)
+}
+
+// failWrite is fail for writes, at the write position.
+func (buf *Buffer) failWrite(op string, err error) error {
+	return buf.failAt(op, buf.base+int64(len(buf.cursor.buffer)), err)
+}
+
+func (buf *Buffer) failAt(op string, offset int64, err error) error {
+	if !buf.sticky {
+		return err
+	}
+
+	if buf.err == nil {
+		buf.err = &BufferError{Offset: offset, Op: op, Err: err}
+	}
+	return buf.err
 }
 
 func (buf *Buffer) Len() int64 {
@@ -20,74 +111,342 @@ func (buf *Buffer) Len() int64 {
 
 func (buf *Buffer) Reset() {
 	buf.cursor.Reset()
+	buf.base = 0
+	buf.runeSize = 0
+	buf.marks = buf.marks[:0]
+	buf.err = nil
 }
 
 func (buf *Buffer) UnTake(n int) {
 	buf.cursor.UnTakeN(n)
 }
 
+// take consumes the next n bytes. It returns io.EOF if no data is left and
+// io.ErrUnexpectedEOF if fewer than n bytes are left, consuming nothing.
+func (buf *Buffer) take(op string, n int) ([]byte, error) {
+	if buf.err != nil {
+		return nil, buf.err
+	}
+
+	switch left := buf.cursor.Len(); {
+	case n < 0:
+		return nil, buf.fail(op, ErrNegativeCount)
+	case n == 0:
+		return []byte{}, nil
+	case left <= 0:
+		return nil, buf.fail(op, io.EOF)
+	case left < int64(n):
+		return nil, buf.fail(op, io.ErrUnexpectedEOF)
+	}
+
+	return buf.cursor.TakeN(n)
+}
+
 func (buf *Buffer) ReadUint8() (v uint8, err error) {
-	v, err = buf.cursor.Read()
-	return
+	data, err := buf.take("ReadUint8", 1)
+	if err != nil {
+		return 0, err
+	}
+	return data[0], nil
 }
 
 func (buf *Buffer) ReadByte() (byte, error) {
 	return buf.ReadUint8()
 }
 
+// UnreadByte steps back one byte, so the last byte read is read again.
+func (buf *Buffer) UnreadByte() error {
+	if buf.cursor.offset <= 0 {
+		return buf.fail("UnreadByte", errors.New("binstruct: UnreadByte at beginning of buffer"))
+	}
+	buf.cursor.offset--
+	return nil
+}
+
+// ReadRune reads one UTF-8 encoded rune. Invalid encodings return
+// utf8.RuneError with size 1.
+func (buf *Buffer) ReadRune() (r rune, size int, err error) {
+	if buf.err != nil {
+		return 0, 0, buf.err
+	}
+	if buf.cursor.EOF() {
+		return 0, 0, buf.fail("ReadRune", io.EOF)
+	}
+
+	r, size = utf8.DecodeRune(buf.cursor.buffer[buf.cursor.offset:])
+	buf.cursor.Skip(size)
+	buf.runeEnd, buf.runeSize = buf.cursor.offset, size
+	return r, size, nil
+}
+
+// UnreadRune steps back over the rune returned by the last ReadRune. It
+// fails if the read position moved since then.
+func (buf *Buffer) UnreadRune() error {
+	if buf.runeSize == 0 || buf.runeEnd != buf.cursor.offset {
+		return buf.fail("UnreadRune", errors.New("binstruct: UnreadRune not preceded by ReadRune"))
+	}
+	buf.cursor.offset -= buf.runeSize
+	buf.runeSize = 0
+	return nil
+}
+
+// Read reads up to len(b) bytes. At the end of the data it returns 0 and
+// io.EOF, which is not recorded in sticky error mode.
 func (buf *Buffer) Read(b []byte) (int, error) {
-	data, err := buf.cursor.TakeN(len(b))
-	if err != nil {
-		return 0, err
+	if buf.err != nil {
+		return 0, buf.err
+	}
+	if len(b) == 0 {
+		return 0, nil
+	}
+	if buf.cursor.EOF() {
+		return 0, io.EOF
 	}
-	n := copy(b, data)
+
+	n := copy(b, buf.cursor.buffer[buf.cursor.offset:])
+	buf.cursor.Skip(n)
 	return n, nil
 }
 
-func (buf *Buffer) ReadLess(b []byte) (int, error) {
-	byteLen := (int)(buf.cursor.Len())
-	buffLen := len(b)
-	if buffLen > byteLen {
-		buffLen = byteLen
+// WriteTo writes the unread data to w and consumes what was written.
+func (buf *Buffer) WriteTo(w io.Writer) (int64, error) {
+	if buf.err != nil {
+		return 0, buf.err
 	}
-	data, err := buf.cursor.TakeN(buffLen)
-	if err != nil {
-		return 0, err
+	if buf.cursor.EOF() {
+		return 0, nil
+	}
+
+	data := buf.cursor.buffer[buf.cursor.offset:]
+	n, err := w.Write(data)
+	buf.cursor.Skip(n)
+	if err == nil && n < len(data) {
+		err = io.ErrShortWrite
+	}
+	return int64(n), err
+}
+
+// ReadFrom appends data from r until io.EOF, which is not returned. With a
+// maximum size set, it reads no more from r than fits and returns
+// ErrBufferFull once the buffer is full.
+func (buf *Buffer) ReadFrom(r io.Reader) (int64, error) {
+	var total int64
+	chunk := make([]byte, 32<<10)
+	for {
+		if buf.err != nil {
+			return total, buf.err
+		}
+
+		buf.autoCompact()
+		p := chunk
+		if free := buf.free(); free == 0 {
+			return total, buf.failWrite("ReadFrom", ErrBufferFull)
+		} else if free > 0 && free < len(p) {
+			p = p[:free]
+		}
+
+		n, err := r.Read(p)
+		if n > 0 {
+			wn, werr := buf.Write(p[:n])
+			total += int64(wn)
+			if werr != nil {
+				return total, werr
+			}
+		}
+
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
 	}
-	n := copy(b, data)
-	return n, nil
 }
 
-func (buf *Buffer) ReadBytes(n int) ([]byte, error) {
-	b := make([]byte, n)
-	n, err := buf.Read(b)
+// Seek sets the read position. Offsets count from the first byte written
+// since the last Reset, including bytes dropped by Compact. Positions
+// outside the bytes still held by the buffer return ErrSeekOutOfRange.
+func (buf *Buffer) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = buf.Offset() + offset
+	case io.SeekEnd:
+		pos = buf.base + int64(len(buf.cursor.buffer)) + offset
+	default:
+		return 0, buf.fail("Seek", errors.New("binstruct: invalid whence"))
+	}
+
+	if pos < buf.base || pos > buf.base+int64(len(buf.cursor.buffer)) {
+		return 0, buf.fail("Seek", ErrSeekOutOfRange)
+	}
+
+	buf.cursor.offset = int(pos - buf.base)
+	return pos, nil
+}
+
+// Offset returns the read position as used by Seek.
+func (buf *Buffer) Offset() int64 {
+	return buf.base + int64(buf.cursor.offset)
+}
+
+// Compact drops the bytes already read and moves the unread data to a new
+// backing array, so slices returned by earlier reads stay valid and the
+// old memory is freed once they are dropped. Offsets keep counting from
+// the same origin, but UnTake and Seek can no longer go back before the
+// read position. Bytes after the oldest active checkpoint are kept for
+// Rollback.
+func (buf *Buffer) Compact() {
+	n := buf.cursor.offset
+	if len(buf.marks) > 0 {
+		n = min(n, int(buf.marks[0]-buf.base))
+	}
+	if n == 0 {
+		return
+	}
+
+	rest := buf.cursor.buffer[n:]
+	buf.cursor.buffer = append(make([]byte, 0, cap(buf.cursor.buffer)-n), rest...)
+	buf.cursor.offset -= n
+	buf.cursor.lastTakeLen = 0
+	buf.base += int64(n)
+	buf.runeSize = 0
+}
+
+// Discard skips the next n unread bytes and compacts the buffer. If fewer
+// than n bytes are unread, it discards them all and returns io.EOF.
+func (buf *Buffer) Discard(n int) (int, error) {
+	if n < 0 {
+		return 0, ErrNegativeCount
+	}
+
+	var err error
+	if left := int(buf.cursor.Len()); n > left {
+		n, err = left, io.EOF
+	}
+
+	buf.cursor.Skip(n)
+	buf.Compact()
+	return n, err
+}
+
+// SetCompactThreshold makes writes compact the buffer first once at least
+// n bytes have been read, 0 disables it. It returns buf for chaining.
+func (buf *Buffer) SetCompactThreshold(n int) *Buffer {
+	buf.compactThreshold = n
+	return buf
+}
+
+func (buf *Buffer) autoCompact() {
+	if buf.compactThreshold > 0 && buf.cursor.offset >= buf.compactThreshold {
+		buf.Compact()
+	}
+}
 
+// Reader returns a Reader which decodes the unread data of buf with byte
+// order, so Unmarshal works on buffered data. Reads through the Reader
+// advance buf, data written to buf later is visible to it.
+func (buf *Buffer) Reader(order binary.ByteOrder, opts ...Option) Reader {
+	return NewReader(bufferReader{buf}, order, false, opts...)
+}
+
+// bufferReader is the io.ReadSeeker view of a Buffer used by Buffer.Reader.
+// It hides the Buffer's own typed read methods from the Reader.
+type bufferReader struct {
+	buf *Buffer
+}
+
+func (r bufferReader) Read(b []byte) (int, error) {
+	return r.buf.Read(b)
+}
+
+func (r bufferReader) Seek(offset int64, whence int) (int64, error) {
+	return r.buf.Seek(offset, whence)
+}
+
+// Len returns the number of unread bytes, so limits can check lengths
+// before allocating.
+func (r bufferReader) Len() int64 {
+	return r.buf.Len()
+}
+
+func (buf *Buffer) ReadLess(b []byte) (int, error) {
+	return buf.Read(b)
+}
+
+// ReadBytes reads exactly n bytes into a new slice.
+func (buf *Buffer) ReadBytes(n int) ([]byte, error) {
+	data, err := buf.take("ReadBytes", n)
 	if err != nil {
 		return nil, err
 	}
-	return b, nil
+	return append([]byte(nil), data...), nil
 }
 
+// ReadUvarint reads an unsigned varint, consuming only its encoded bytes.
+// A truncated varint returns io.ErrUnexpectedEOF and consumes nothing.
 func (buf *Buffer) ReadUvarint() (uint64, error) {
-	data, err := buf.cursor.TakeN(8)
+	data, err := buf.peekVarint("ReadUvarint")
 	if err != nil {
 		return 0, err
 	}
-	v, _ := GetUvarint(data)
+
+	v, n := GetUvarint(data)
+	if err = buf.takeVarint("ReadUvarint", data, n); err != nil {
+		return 0, err
+	}
 	return v, nil
 }
 
+// ReadVarint reads a zigzag encoded signed varint, consuming only its
+// encoded bytes.
 func (buf *Buffer) ReadVarint() (int64, error) {
-	data, err := buf.cursor.TakeN(8)
+	data, err := buf.peekVarint("ReadVarint")
 	if err != nil {
 		return 0, err
 	}
-	v, _ := GetVarint(data)
+
+	v, n := GetVarint(data)
+	if err = buf.takeVarint("ReadVarint", data, n); err != nil {
+		return 0, err
+	}
 	return v, nil
 }
 
+// peekVarint returns the unread bytes a varint can span.
+func (buf *Buffer) peekVarint(op string) ([]byte, error) {
+	if buf.err != nil {
+		return nil, buf.err
+	}
+
+	n := buf.cursor.Len()
+	if n == 0 {
+		return nil, buf.fail(op, io.EOF)
+	}
+	if n > binary.MaxVarintLen64 {
+		n = binary.MaxVarintLen64
+	}
+	return buf.cursor.Peek(int(n))
+}
+
+// takeVarint consumes a varint of n bytes decoded from data, n as returned
+// by binary.Uvarint.
+func (buf *Buffer) takeVarint(op string, data []byte, n int) error {
+	switch {
+	case n > 0:
+		buf.cursor.Skip(n)
+		return nil
+	case n == 0 && len(data) < binary.MaxVarintLen64:
+		return buf.fail(op, io.ErrUnexpectedEOF)
+	default:
+		return buf.fail(op, ErrVarintOverflow)
+	}
+}
+
 func (buf *Buffer) ReadUint16BE() (v uint16, err error) {
-	data, err := buf.cursor.TakeN(2)
+	data, err := buf.take("ReadUint16BE", 2)
 	if err != nil {
 		return 0, err
 	}
@@ -96,7 +455,7 @@ func (buf *Buffer) ReadUint16BE() (v uint16, err error) {
 }
 
 func (buf *Buffer) ReadUint16LE() (v uint16, err error) {
-	data, err := buf.cursor.TakeN(2)
+	data, err := buf.take("ReadUint16LE", 2)
 	if err != nil {
 		return 0, err
 	}
@@ -106,7 +465,14 @@ func (buf *Buffer) ReadUint16LE() (v uint16, err error) {
 }
 
 func (buf *Buffer) ReadBytesTill(delim byte) (data []byte, err error) {
+	if buf.err != nil {
+		return nil, buf.err
+	}
+
 	data, err = buf.cursor.Till(delim)
+	if err != nil {
+		return nil, buf.fail("ReadBytesTill", err)
+	}
 	return
 }
 
@@ -118,6 +484,34 @@ func (buf *Buffer) ReadString(n int) (string, error) {
 	return string(data), nil
 }
 
+func (buf *Buffer) ReadBCD(n int) (string, error) {
+	start := buf.Offset()
+	data, err := buf.take("ReadBCD", n)
+	if err != nil {
+		return "", err
+	}
+
+	digits, err := DecodeBCD(data)
+	if err != nil {
+		return "", buf.failAt("ReadBCD", start, err)
+	}
+	return digits, nil
+}
+
+func (buf *Buffer) ReadTBCD(n int) (string, error) {
+	start := buf.Offset()
+	data, err := buf.take("ReadTBCD", n)
+	if err != nil {
+		return "", err
+	}
+
+	digits, err := DecodeTBCD(data)
+	if err != nil {
+		return "", buf.failAt("ReadTBCD", start, err)
+	}
+	return digits, nil
+}
+
 func (buf *Buffer) ReadLine() (line string, err error) {
 	data, err := buf.ReadBytesTill('\n')
 
@@ -129,7 +523,7 @@ func (buf *Buffer) ReadLine() (line string, err error) {
 }
 
 func (buf *Buffer) ReadUint24BE() (v uint32, err error) {
-	data, err := buf.cursor.TakeN(3)
+	data, err := buf.take("ReadUint24BE", 3)
 	if err != nil {
 		return 0, err
 	}
@@ -138,7 +532,7 @@ func (buf *Buffer) ReadUint24BE() (v uint32, err error) {
 }
 
 func (buf *Buffer) ReadUint24LE() (v uint32, err error) {
-	data, err := buf.cursor.TakeN(3)
+	data, err := buf.take("ReadUint24LE", 3)
 	if err != nil {
 		return 0, err
 	}
@@ -147,7 +541,7 @@ func (buf *Buffer) ReadUint24LE() (v uint32, err error) {
 }
 
 func (buf *Buffer) ReadUint32BE() (v uint32, err error) {
-	data, err := buf.cursor.TakeN(4)
+	data, err := buf.take("ReadUint32BE", 4)
 	if err != nil {
 		return 0, err
 	}
@@ -156,7 +550,7 @@ func (buf *Buffer) ReadUint32BE() (v uint32, err error) {
 }
 
 func (buf *Buffer) ReadUint32LE() (v uint32, err error) {
-	data, err := buf.cursor.TakeN(4)
+	data, err := buf.take("ReadUint32LE", 4)
 	if err != nil {
 		return 0, err
 	}
@@ -165,7 +559,7 @@ func (buf *Buffer) ReadUint32LE() (v uint32, err error) {
 }
 
 func (buf *Buffer) ReadUint40BE() (v uint64, err error) {
-	data, err := buf.cursor.TakeN(5)
+	data, err := buf.take("ReadUint40BE", 5)
 	if err != nil {
 		return 0, err
 	}
@@ -174,7 +568,7 @@ func (buf *Buffer) ReadUint40BE() (v uint64, err error) {
 }
 
 func (buf *Buffer) ReadUint40LE() (v uint64, err error) {
-	data, err := buf.cursor.TakeN(5)
+	data, err := buf.take("ReadUint40LE", 5)
 	if err != nil {
 		return 0, err
 	}
@@ -183,7 +577,7 @@ func (buf *Buffer) ReadUint40LE() (v uint64, err error) {
 }
 
 func (buf *Buffer) ReadUint48BE() (v uint64, err error) {
-	data, err := buf.cursor.TakeN(6)
+	data, err := buf.take("ReadUint48BE", 6)
 	if err != nil {
 		return 0, err
 	}
@@ -192,7 +586,7 @@ func (buf *Buffer) ReadUint48BE() (v uint64, err error) {
 }
 
 func (buf *Buffer) ReadUint48LE() (v uint64, err error) {
-	data, err := buf.cursor.TakeN(6)
+	data, err := buf.take("ReadUint48LE", 6)
 	if err != nil {
 		return 0, err
 	}
@@ -201,7 +595,7 @@ func (buf *Buffer) ReadUint48LE() (v uint64, err error) {
 }
 
 func (buf *Buffer) ReadUint56BE() (v uint64, err error) {
-	data, err := buf.cursor.TakeN(7)
+	data, err := buf.take("ReadUint56BE", 7)
 	if err != nil {
 		return 0, err
 	}
@@ -210,7 +604,7 @@ func (buf *Buffer) ReadUint56BE() (v uint64, err error) {
 }
 
 func (buf *Buffer) ReadUint56LE() (v uint64, err error) {
-	data, err := buf.cursor.TakeN(7)
+	data, err := buf.take("ReadUint56LE", 7)
 	if err != nil {
 		return 0, err
 	}
@@ -219,7 +613,7 @@ func (buf *Buffer) ReadUint56LE() (v uint64, err error) {
 }
 
 func (buf *Buffer) ReadUint64BE() (v uint64, err error) {
-	data, err := buf.cursor.TakeN(8)
+	data, err := buf.take("ReadUint64BE", 8)
 	if err != nil {
 		return 0, err
 	}
@@ -228,7 +622,7 @@ func (buf *Buffer) ReadUint64BE() (v uint64, err error) {
 }
 
 func (buf *Buffer) ReadUint64LE() (v uint64, err error) {
-	data, err := buf.cursor.TakeN(8)
+	data, err := buf.take("ReadUint64LE", 8)
 	if err != nil {
 		return 0, err
 	}
@@ -236,8 +630,44 @@ func (buf *Buffer) ReadUint64LE() (v uint64, err error) {
 	return
 }
 
+func (buf *Buffer) ReadFloat16BE() (v float32, err error) {
+	data, err := buf.take("ReadFloat16BE", 2)
+	if err != nil {
+		return 0, err
+	}
+	v = GetFloat16BE(data)
+	return
+}
+
+func (buf *Buffer) ReadFloat16LE() (v float32, err error) {
+	data, err := buf.take("ReadFloat16LE", 2)
+	if err != nil {
+		return 0, err
+	}
+	v = GetFloat16LE(data)
+	return
+}
+
+func (buf *Buffer) ReadBFloat16BE() (v float32, err error) {
+	data, err := buf.take("ReadBFloat16BE", 2)
+	if err != nil {
+		return 0, err
+	}
+	v = GetBFloat16BE(data)
+	return
+}
+
+func (buf *Buffer) ReadBFloat16LE() (v float32, err error) {
+	data, err := buf.take("ReadBFloat16LE", 2)
+	if err != nil {
+		return 0, err
+	}
+	v = GetBFloat16LE(data)
+	return
+}
+
 func (buf *Buffer) ReadFloat32BE() (v float32, err error) {
-	data, err := buf.cursor.TakeN(4)
+	data, err := buf.take("ReadFloat32BE", 4)
 	if err != nil {
 		return 0, err
 	}
@@ -246,7 +676,7 @@ func (buf *Buffer) ReadFloat32BE() (v float32, err error) {
 }
 
 func (buf *Buffer) ReadFloat32LE() (v float32, err error) {
-	data, err := buf.cursor.TakeN(4)
+	data, err := buf.take("ReadFloat32LE", 4)
 	if err != nil {
 		return 0, err
 	}
@@ -255,7 +685,7 @@ func (buf *Buffer) ReadFloat32LE() (v float32, err error) {
 }
 
 func (buf *Buffer) ReadFloat64BE() (v float64, err error) {
-	data, err := buf.cursor.TakeN(8)
+	data, err := buf.take("ReadFloat64BE", 8)
 	if err != nil {
 		return 0, err
 	}
@@ -264,7 +694,7 @@ func (buf *Buffer) ReadFloat64BE() (v float64, err error) {
 }
 
 func (buf *Buffer) ReadFloat64LE() (v float64, err error) {
-	data, err := buf.cursor.TakeN(8)
+	data, err := buf.take("ReadFloat64LE", 8)
 	if err != nil {
 		return 0, err
 	}
@@ -288,12 +718,18 @@ func (buf *Buffer) ReadInt16LE() (int16, error) {
 }
 
 func (buf *Buffer) ReadInt24BE() (int32, error) {
-	data, err := buf.ReadUint24BE()
-	return int32(data), err
+	data, err := buf.take("ReadInt24BE", 3)
+	if err != nil {
+		return 0, err
+	}
+	return GetInt24BE(data), nil
 }
 func (buf *Buffer) ReadInt24LE() (int32, error) {
-	data, err := buf.ReadUint24LE()
-	return int32(data), err
+	data, err := buf.take("ReadInt24LE", 3)
+	if err != nil {
+		return 0, err
+	}
+	return GetInt24LE(data), nil
 }
 func (buf *Buffer) ReadInt32BE() (int32, error) {
 	data, err := buf.ReadUint32BE()
@@ -304,28 +740,46 @@ func (buf *Buffer) ReadInt32LE() (int32, error) {
 	return int32(data), err
 }
 func (buf *Buffer) ReadInt40BE() (int64, error) {
-	data, err := buf.ReadUint40BE()
-	return int64(data), err
+	data, err := buf.take("ReadInt40BE", 5)
+	if err != nil {
+		return 0, err
+	}
+	return GetInt40BE(data), nil
 }
 func (buf *Buffer) ReadInt40LE() (int64, error) {
-	data, err := buf.ReadUint40LE()
-	return int64(data), err
+	data, err := buf.take("ReadInt40LE", 5)
+	if err != nil {
+		return 0, err
+	}
+	return GetInt40LE(data), nil
 }
 func (buf *Buffer) ReadInt48BE() (int64, error) {
-	data, err := buf.ReadUint48BE()
-	return int64(data), err
+	data, err := buf.take("ReadInt48BE", 6)
+	if err != nil {
+		return 0, err
+	}
+	return GetInt48BE(data), nil
 }
 func (buf *Buffer) ReadInt48LE() (int64, error) {
-	data, err := buf.ReadUint48LE()
-	return int64(data), err
+	data, err := buf.take("ReadInt48LE", 6)
+	if err != nil {
+		return 0, err
+	}
+	return GetInt48LE(data), nil
 }
 func (buf *Buffer) ReadInt56BE() (int64, error) {
-	data, err := buf.ReadUint56BE()
-	return int64(data), err
+	data, err := buf.take("ReadInt56BE", 7)
+	if err != nil {
+		return 0, err
+	}
+	return GetInt56BE(data), nil
 }
 func (buf *Buffer) ReadInt56LE() (int64, error) {
-	data, err := buf.ReadUint56LE()
-	return int64(data), err
+	data, err := buf.take("ReadInt56LE", 7)
+	if err != nil {
+		return 0, err
+	}
+	return GetInt56LE(data), nil
 }
 func (buf *Buffer) ReadInt64BE() (int64, error) {
 	data, err := buf.ReadUint64BE()
@@ -353,142 +807,229 @@ func (buf *Buffer) ReadUintLE() (uint, error) {
 }
 
 func (buf *Buffer) Take(n int) (data []byte, err error) {
-	return buf.cursor.TakeN(n)
+	return buf.take("Take", n)
 }
 
+// Write appends b. With a maximum size set, it writes as much of b as fits
+// and returns ErrBufferFull if that is not all of it.
 func (buf *Buffer) Write(b []byte) (int, error) {
+	if buf.err != nil {
+		return 0, buf.err
+	}
+
+	buf.autoCompact()
+	if free := buf.free(); free >= 0 && free < len(b) {
+		err := buf.failWrite("Write", ErrBufferFull)
+		n, _ := buf.cursor.Grow(b[:free])
+		return n, err
+	}
+	return buf.cursor.Grow(b)
+}
+
+// writeAll appends b, or nothing if it doesn't fit. The typed Write methods
+// use it so a full buffer never holds half a value.
+func (buf *Buffer) writeAll(op string, b []byte) (int, error) {
+	if buf.err != nil {
+		return 0, buf.err
+	}
+
+	buf.autoCompact()
+	if free := buf.free(); free >= 0 && free < len(b) {
+		return 0, buf.failWrite(op, ErrBufferFull)
+	}
 	return buf.cursor.Grow(b)
 }
 
+// free returns the number of bytes that can still be written, or -1 if the
+// size is not limited.
+func (buf *Buffer) free() int {
+	if buf.maxSize <= 0 {
+		return -1
+	}
+	if n := buf.maxSize - len(buf.cursor.buffer); n > 0 {
+		return n
+	}
+	return 0
+}
+
+func (buf *Buffer) WriteByte(c byte) error {
+	_, err := buf.writeAll("WriteByte", []byte{c})
+	return err
+}
+
+func (buf *Buffer) WriteRune(r rune) (int, error) {
+	return buf.writeAll("WriteRune", utf8.AppendRune(nil, r))
+}
+
 func (buf *Buffer) WriteString(s string) (int, error) {
-	return buf.cursor.Grow([]byte(s))
+	return buf.Write([]byte(s))
 }
 
 func (buf *Buffer) WriteLine(lines string) (int, error) {
 	return buf.WriteString(lines + "\n")
 }
 
+func (buf *Buffer) WriteBCD(digits string, n int) (int, error) {
+	dbuf, err := EncodeBCD(digits, n)
+	if err != nil {
+		return 0, err
+	}
+	return buf.writeAll("WriteBCD", dbuf)
+}
+
+func (buf *Buffer) WriteTBCD(digits string, n int) (int, error) {
+	dbuf, err := EncodeTBCD(digits, n)
+	if err != nil {
+		return 0, err
+	}
+	return buf.writeAll("WriteTBCD", dbuf)
+}
+
 func (buf *Buffer) WriteUvarint(v uint64) (int, error) {
-	dbuf := make([]byte, 8)
-	PutUvarint(dbuf, v)
-	return buf.Write(dbuf)
+	dbuf := make([]byte, binary.MaxVarintLen64)
+	n := PutUvarint(dbuf, v)
+	return buf.writeAll("WriteUvarint", dbuf[:n])
 }
 
 func (buf *Buffer) WriteVarint(v int64) (int, error) {
-	dbuf := make([]byte, 8)
-	PutVarint(dbuf, v)
-	return buf.Write(dbuf)
+	dbuf := make([]byte, binary.MaxVarintLen64)
+	n := PutVarint(dbuf, v)
+	return buf.writeAll("WriteVarint", dbuf[:n])
 }
 
 func (buf *Buffer) WriteUint8(v uint8) (int, error) {
-	return buf.Write([]byte{v})
+	return buf.writeAll("WriteUint8", []byte{v})
 }
 
 func (buf *Buffer) WriteUint16BE(v uint16) (int, error) {
 	dbuf := make([]byte, 2)
 	PutUint16BE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint16BE", dbuf)
 }
 
 func (buf *Buffer) WriteUint16LE(v uint16) (int, error) {
 	dbuf := make([]byte, 2)
 	PutUint16LE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint16LE", dbuf)
 }
 func (buf *Buffer) WriteUint24BE(v uint32) (int, error) {
 	dbuf := make([]byte, 3)
 	PutUint24BE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint24BE", dbuf)
 }
 
 func (buf *Buffer) WriteUint24LE(v uint32) (int, error) {
 	dbuf := make([]byte, 3)
 	PutUint24LE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint24LE", dbuf)
 }
 
 func (buf *Buffer) WriteUint32BE(v uint32) (int, error) {
 	dbuf := make([]byte, 4)
 	PutUint32BE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint32BE", dbuf)
 }
 
 func (buf *Buffer) WriteUint32LE(v uint32) (int, error) {
 	dbuf := make([]byte, 4)
 	PutUint32LE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint32LE", dbuf)
 }
 
 func (buf *Buffer) WriteUint40BE(v uint64) (int, error) {
 	dbuf := make([]byte, 5)
 	PutUint40BE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint40BE", dbuf)
 }
 
 func (buf *Buffer) WriteUint40LE(v uint64) (int, error) {
 	dbuf := make([]byte, 5)
 	PutUint40LE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint40LE", dbuf)
 }
 
 func (buf *Buffer) WriteUint48BE(v uint64) (int, error) {
 	dbuf := make([]byte, 6)
 	PutUint48BE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint48BE", dbuf)
 }
 
 func (buf *Buffer) WriteUint48LE(v uint64) (int, error) {
 	dbuf := make([]byte, 6)
 	PutUint48LE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint48LE", dbuf)
 }
 
 func (buf *Buffer) WriteUint56BE(v uint64) (int, error) {
 	dbuf := make([]byte, 7)
 	PutUint56BE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint56BE", dbuf)
 }
 
 func (buf *Buffer) WriteUint56LE(v uint64) (int, error) {
 	dbuf := make([]byte, 7)
 	PutUint56LE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint56LE", dbuf)
 }
 
 func (buf *Buffer) WriteUint64BE(v uint64) (int, error) {
 	dbuf := make([]byte, 8)
 	PutUint64BE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint64BE", dbuf)
 }
 
 func (buf *Buffer) WriteUint64LE(v uint64) (int, error) {
 	dbuf := make([]byte, 8)
 	PutUint64LE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteUint64LE", dbuf)
+}
+
+func (buf *Buffer) WriteFloat16BE(v float32) (int, error) {
+	dbuf := make([]byte, 2)
+	PutFloat16BE(dbuf, v)
+	return buf.writeAll("WriteFloat16BE", dbuf)
+}
+
+func (buf *Buffer) WriteFloat16LE(v float32) (int, error) {
+	dbuf := make([]byte, 2)
+	PutFloat16LE(dbuf, v)
+	return buf.writeAll("WriteFloat16LE", dbuf)
+}
+
+func (buf *Buffer) WriteBFloat16BE(v float32) (int, error) {
+	dbuf := make([]byte, 2)
+	PutBFloat16BE(dbuf, v)
+	return buf.writeAll("WriteBFloat16BE", dbuf)
+}
+
+func (buf *Buffer) WriteBFloat16LE(v float32) (int, error) {
+	dbuf := make([]byte, 2)
+	PutBFloat16LE(dbuf, v)
+	return buf.writeAll("WriteBFloat16LE", dbuf)
 }
 
 func (buf *Buffer) WriteFloat32BE(v float32) (int, error) {
 	dbuf := make([]byte, 4)
 	PutFloat32BE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteFloat32BE", dbuf)
 }
 
 func (buf *Buffer) WriteFloat32LE(v float32) (int, error) {
 	dbuf := make([]byte, 4)
 	PutFloat32LE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteFloat32LE", dbuf)
 }
 
 func (buf *Buffer) WriteFloat64BE(v float64) (int, error) {
 	dbuf := make([]byte, 8)
 	PutFloat64BE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteFloat64BE", dbuf)
 }
 
 func (buf *Buffer) WriteFloat64LE(v float64) (int, error) {
 	dbuf := make([]byte, 8)
 	PutFloat64LE(dbuf, v)
-	return buf.Write(dbuf)
+	return buf.writeAll("WriteFloat64LE", dbuf)
 }
 
 func (buf *Buffer) WriteInt8(v int8) (int, error) {
@@ -0,0 +1,623 @@
+package gocodec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BufferUvarint(t *testing.T) {
+	values := []uint64{0, 1, 127, 128, 300, 1<<32 - 1, 1 << 56, math.MaxUint64}
+
+	var buf Buffer
+	var want []byte
+	for _, v := range values {
+		n, err := buf.WriteUvarint(v)
+		require.NoError(t, err)
+		require.Equal(t, UvarintSize(v), n)
+		want = binary.AppendUvarint(want, v)
+	}
+	require.Equal(t, int64(len(want)), buf.Len())
+
+	for _, v := range values {
+		got, err := buf.ReadUvarint()
+		require.NoError(t, err)
+		require.Equal(t, v, got)
+	}
+
+	_, err := buf.ReadUvarint()
+	require.ErrorIs(t, err, io.EOF)
+
+	buf.Reset()
+	_, err = buf.Write(want)
+	require.NoError(t, err)
+	for _, v := range values {
+		got, err := buf.ReadUvarint()
+		require.NoError(t, err)
+		require.Equal(t, v, got)
+	}
+}
+
+func Test_BufferVarint(t *testing.T) {
+	values := []int64{0, -1, 1, -64, 64, math.MaxInt32, math.MinInt32, math.MaxInt64, math.MinInt64}
+
+	var buf Buffer
+	var want []byte
+	for _, v := range values {
+		n, err := buf.WriteVarint(v)
+		require.NoError(t, err)
+		require.Equal(t, VarintSize(v), n)
+		want = binary.AppendVarint(want, v)
+	}
+
+	got := make([]byte, len(want))
+	_, err := buf.Read(got)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	_, err = buf.Write(want)
+	require.NoError(t, err)
+	for _, v := range values {
+		got, err := buf.ReadVarint()
+		require.NoError(t, err)
+		require.Equal(t, v, got)
+	}
+}
+
+func Test_BufferVarintErrors(t *testing.T) {
+	var buf Buffer
+	_, err := buf.Write([]byte{0x80, 0x80})
+	require.NoError(t, err)
+
+	_, err = buf.ReadUvarint()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.Equal(t, int64(2), buf.Len())
+
+	_, err = buf.Write([]byte{0x01, 0x05})
+	require.NoError(t, err)
+	v, err := buf.ReadUvarint()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1<<14), v)
+	require.Equal(t, int64(1), buf.Len())
+
+	buf.Reset()
+	_, err = buf.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x02})
+	require.NoError(t, err)
+	_, err = buf.ReadUvarint()
+	require.ErrorIs(t, err, ErrVarintOverflow)
+
+	buf.Reset()
+	_, err = buf.Write([]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01})
+	require.NoError(t, err)
+	_, err = buf.ReadVarint()
+	require.ErrorIs(t, err, ErrVarintOverflow)
+}
+
+func Test_BufferOddWidthInts(t *testing.T) {
+	tests := []struct {
+		name  string
+		bits  uint
+		write func(buf *Buffer, v int64) (int, error)
+		read  func(buf *Buffer) (int64, error)
+		get   func(b []byte) int64
+	}{
+		{"24BE", 24,
+			func(buf *Buffer, v int64) (int, error) { return buf.WriteInt24BE(int32(v)) },
+			func(buf *Buffer) (int64, error) { v, err := buf.ReadInt24BE(); return int64(v), err },
+			func(b []byte) int64 { return int64(GetInt24BE(b)) }},
+		{"24LE", 24,
+			func(buf *Buffer, v int64) (int, error) { return buf.WriteInt24LE(int32(v)) },
+			func(buf *Buffer) (int64, error) { v, err := buf.ReadInt24LE(); return int64(v), err },
+			func(b []byte) int64 { return int64(GetInt24LE(b)) }},
+		{"40BE", 40, (*Buffer).WriteInt40BE, (*Buffer).ReadInt40BE, GetInt40BE},
+		{"40LE", 40, (*Buffer).WriteInt40LE, (*Buffer).ReadInt40LE, GetInt40LE},
+		{"48BE", 48, (*Buffer).WriteInt48BE, (*Buffer).ReadInt48BE, GetInt48BE},
+		{"48LE", 48, (*Buffer).WriteInt48LE, (*Buffer).ReadInt48LE, GetInt48LE},
+		{"56BE", 56, (*Buffer).WriteInt56BE, (*Buffer).ReadInt56BE, GetInt56BE},
+		{"56LE", 56, (*Buffer).WriteInt56LE, (*Buffer).ReadInt56LE, GetInt56LE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			max := int64(1)<<(tt.bits-1) - 1
+			min := -max - 1
+			values := []int64{0, 1, -1, 2, -2, max, min, max - 1, min + 1, 0x5A}
+
+			for _, v := range values {
+				var buf Buffer
+				n, err := tt.write(&buf, v)
+				require.NoError(t, err)
+				require.Equal(t, int(tt.bits/8), n)
+
+				b, err := buf.cursor.Peek(n)
+				require.NoError(t, err)
+				require.Equal(t, v, tt.get(b), "get %d", v)
+
+				var order binary.ByteOrder = binary.BigEndian
+				if strings.HasSuffix(tt.name, "LE") {
+					order = binary.LittleEndian
+				}
+				x, err := NewReaderFromBytes(b, order, false).ReadIntX(n)
+				require.NoError(t, err)
+				require.Equal(t, v, x, "reader %d", v)
+
+				got, err := tt.read(&buf)
+				require.NoError(t, err)
+				require.Equal(t, v, got, "read %d", v)
+			}
+
+			var buf Buffer
+			_, err := tt.read(&buf)
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_BufferSeek(t *testing.T) {
+	var buf Buffer
+	_, err := buf.Write([]byte{1, 2, 3, 4})
+	require.NoError(t, err)
+
+	var _ io.Seeker = &buf
+
+	pos, err := buf.Seek(2, io.SeekStart)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), pos)
+	v, err := buf.ReadUint8()
+	require.NoError(t, err)
+	require.Equal(t, uint8(3), v)
+
+	pos, err = buf.Seek(-2, io.SeekCurrent)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), pos)
+
+	pos, err = buf.Seek(0, io.SeekEnd)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), pos)
+	require.Equal(t, int64(0), buf.Len())
+
+	_, err = buf.Seek(1, io.SeekEnd)
+	require.ErrorIs(t, err, ErrSeekOutOfRange)
+	_, err = buf.Seek(-1, io.SeekStart)
+	require.ErrorIs(t, err, ErrSeekOutOfRange)
+}
+
+func Test_BufferReader(t *testing.T) {
+	type frame struct {
+		Len     uint16
+		Payload []byte `bin:"len:Len"`
+		Kind    uint8
+		Flags   uint32 `bin:"le"`
+	}
+
+	var buf Buffer
+	_, err := buf.Write([]byte{0x00, 0x03, 'a', 'b', 'c', 0x07, 0x01, 0x00, 0x00, 0x00})
+	require.NoError(t, err)
+	_, err = buf.Write([]byte{0x00})
+	require.NoError(t, err)
+
+	r := buf.Reader(binary.BigEndian)
+	var f frame
+	require.NoError(t, r.Unmarshal(&f))
+	require.Equal(t, frame{Len: 3, Payload: []byte("abc"), Kind: 7, Flags: 1}, f)
+	require.Equal(t, int64(1), buf.Len())
+
+	b, err := r.Peek(1)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x00}, b)
+	require.Equal(t, int64(1), buf.Len())
+
+	// Second frame is incomplete until more data arrives.
+	err = r.Unmarshal(&f)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	_, err = buf.Seek(-1, io.SeekEnd)
+	require.NoError(t, err)
+	_, err = buf.Write([]byte{0x01, 'z', 0x09, 0x02, 0x00, 0x00, 0x00})
+	require.NoError(t, err)
+	require.NoError(t, r.Unmarshal(&f))
+	require.Equal(t, frame{Len: 1, Payload: []byte("z"), Kind: 9, Flags: 2}, f)
+
+	err = r.Unmarshal(&f)
+	require.ErrorIs(t, err, io.EOF)
+
+	var huge struct {
+		Payload []byte `bin:"len:1000000"`
+	}
+	_, err = buf.Write([]byte{1, 2})
+	require.NoError(t, err)
+	err = buf.Reader(binary.BigEndian, WithLimits(Limits{MaxAlloc: 1 << 30})).Unmarshal(&huge)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func Test_BufferIO(t *testing.T) {
+	content := []byte("binstruct buffer ✓ io contracts")
+
+	var buf Buffer
+	_, err := buf.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, iotest.TestReader(&buf, content))
+
+	var (
+		_ io.ByteScanner  = &buf
+		_ io.RuneScanner  = &buf
+		_ io.ReaderFrom   = &buf
+		_ io.WriterTo     = &buf
+		_ io.StringWriter = &buf
+		_ io.ByteWriter   = &buf
+	)
+
+	buf.Reset()
+	n, err := buf.ReadFrom(iotest.HalfReader(bytes.NewReader(content)))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(content)), n)
+
+	var out bytes.Buffer
+	_, err = io.Copy(&out, iotest.OneByteReader(&buf))
+	require.NoError(t, err)
+	require.Equal(t, content, out.Bytes())
+
+	buf.Reset()
+	_, err = buf.WriteString("abc")
+	require.NoError(t, err)
+	out.Reset()
+	n, err = buf.WriteTo(&out)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+	require.Equal(t, "abc", out.String())
+	require.Equal(t, int64(0), buf.Len())
+
+	_, err = buf.ReadFrom(iotest.ErrReader(io.ErrClosedPipe))
+	require.ErrorIs(t, err, io.ErrClosedPipe)
+}
+
+func Test_BufferShortRead(t *testing.T) {
+	var buf Buffer
+	_, err := buf.Write([]byte{1, 2, 3})
+	require.NoError(t, err)
+
+	b := make([]byte, 5)
+	n, err := buf.Read(b)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	n, err = buf.Read(b)
+	require.Equal(t, 0, n)
+	require.ErrorIs(t, err, io.EOF)
+
+	_, err = buf.ReadUint16BE()
+	require.ErrorIs(t, err, io.EOF)
+
+	require.NoError(t, buf.WriteByte(4))
+	_, err = buf.ReadUint16BE()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.Equal(t, int64(1), buf.Len())
+
+	_, err = buf.ReadBytes(2)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	c, err := buf.ReadByte()
+	require.NoError(t, err)
+	require.Equal(t, byte(4), c)
+	require.NoError(t, buf.UnreadByte())
+	c, err = buf.ReadByte()
+	require.NoError(t, err)
+	require.Equal(t, byte(4), c)
+}
+
+func Test_BufferRunes(t *testing.T) {
+	var buf Buffer
+	_, err := buf.WriteRune('ü')
+	require.NoError(t, err)
+	_, err = buf.WriteString("x\xff")
+	require.NoError(t, err)
+
+	require.Error(t, buf.UnreadRune())
+
+	r, size, err := buf.ReadRune()
+	require.NoError(t, err)
+	require.Equal(t, 'ü', r)
+	require.Equal(t, 2, size)
+
+	require.NoError(t, buf.UnreadRune())
+	require.Error(t, buf.UnreadRune())
+
+	r, _, err = buf.ReadRune()
+	require.NoError(t, err)
+	require.Equal(t, 'ü', r)
+
+	r, _, err = buf.ReadRune()
+	require.NoError(t, err)
+	require.Equal(t, 'x', r)
+
+	r, size, err = buf.ReadRune()
+	require.NoError(t, err)
+	require.Equal(t, utf8.RuneError, r)
+	require.Equal(t, 1, size)
+
+	_, _, err = buf.ReadRune()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func Test_BufferMaxSize(t *testing.T) {
+	buf := NewBufferFromBytes(make([]byte, 0, 8)).SetMaxSize(8)
+
+	n, err := buf.Write([]byte{1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+
+	n, err = buf.WriteUint32BE(0xDEADBEEF)
+	require.ErrorIs(t, err, ErrBufferFull)
+	require.Equal(t, 0, n)
+	require.Equal(t, int64(5), buf.Len())
+
+	n, err = buf.Write([]byte{6, 7, 8, 9})
+	require.ErrorIs(t, err, ErrBufferFull)
+	require.Equal(t, 3, n)
+	require.Equal(t, int64(8), buf.Len())
+
+	require.ErrorIs(t, buf.WriteByte(10), ErrBufferFull)
+
+	n64, err := buf.ReadFrom(bytes.NewReader([]byte{1}))
+	require.ErrorIs(t, err, ErrBufferFull)
+	require.Equal(t, int64(0), n64)
+
+	b, err := buf.ReadBytes(8)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, b)
+
+	buf.Reset()
+	_, err = buf.WriteUint64LE(1)
+	require.NoError(t, err)
+
+	_, err = buf.SetMaxSize(0).Write(make([]byte, 100))
+	require.NoError(t, err)
+
+	buf = NewBufferFromBytes(nil).SetMaxSize(2)
+	n, err = buf.WriteString("hello")
+	require.ErrorIs(t, err, ErrBufferFull)
+	require.Equal(t, 2, n)
+	require.Equal(t, int64(2), buf.Len())
+
+	buf = NewBufferFromBytes(nil).SetMaxSize(5)
+	n, err = buf.WriteLine("hello")
+	require.ErrorIs(t, err, ErrBufferFull)
+	require.Equal(t, 5, n)
+	require.Equal(t, int64(5), buf.Len())
+}
+
+func Test_NewBufferFromBytes(t *testing.T) {
+	backing := make([]byte, 0, 16)
+	buf := NewBufferFromBytes(backing)
+	_, err := buf.WriteUint16BE(0x0102)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x01, 0x02}, backing[:2])
+
+	buf = NewBufferFromBytes([]byte{0xAB, 0xCD})
+	v, err := buf.ReadUint16LE()
+	require.NoError(t, err)
+	require.Equal(t, uint16(0xCDAB), v)
+}
+
+func Test_BufferCompact(t *testing.T) {
+	var buf Buffer
+	_, err := buf.Write([]byte{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+
+	_, err = buf.ReadUint16BE()
+	require.NoError(t, err)
+	buf.Compact()
+	require.Equal(t, int64(4), buf.Len())
+	require.Equal(t, int64(2), buf.Offset())
+	require.Equal(t, []byte{3, 4, 5, 6}, buf.cursor.buffer)
+
+	pos, err := buf.Seek(0, io.SeekEnd)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), pos)
+	_, err = buf.Seek(1, io.SeekStart)
+	require.ErrorIs(t, err, ErrSeekOutOfRange)
+	pos, err = buf.Seek(3, io.SeekStart)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), pos)
+
+	n, err := buf.Discard(2)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, int64(5), buf.Offset())
+	require.Equal(t, []byte{6}, buf.cursor.buffer)
+
+	n, err = buf.Discard(3)
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, 1, n)
+	require.Equal(t, int64(0), buf.Len())
+	require.Equal(t, int64(6), buf.Offset())
+
+	buf.Reset()
+	require.Equal(t, int64(0), buf.Offset())
+}
+
+func Test_BufferCompactThreshold(t *testing.T) {
+	buf := NewBufferFromBytes(nil).SetCompactThreshold(4).SetMaxSize(8)
+
+	for i := 0; i < 100; i++ {
+		_, err := buf.WriteUint16LE(uint16(i))
+		require.NoError(t, err)
+		_, err = buf.WriteUint16LE(uint16(i))
+		require.NoError(t, err)
+
+		v, err := buf.ReadUint16LE()
+		require.NoError(t, err)
+		require.Equal(t, uint16(i), v)
+		v, err = buf.ReadUint16LE()
+		require.NoError(t, err)
+		require.Equal(t, uint16(i), v)
+	}
+	require.LessOrEqual(t, len(buf.cursor.buffer), 8)
+	require.Equal(t, int64(400), buf.Offset())
+}
+
+func Test_BufferReaderAfterCompact(t *testing.T) {
+	var buf Buffer
+	_, err := buf.Write([]byte{0xFF, 0xFF, 0x00, 0x02, 0x01})
+	require.NoError(t, err)
+	_, err = buf.Discard(2)
+	require.NoError(t, err)
+
+	var v struct {
+		A uint16
+		B uint16
+	}
+	err = buf.Reader(binary.BigEndian).Unmarshal(&v)
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "B", decodeErr.Path)
+	require.Equal(t, int64(4), decodeErr.Offset)
+}
+
+func Test_BufferStickyErrors(t *testing.T) {
+	buf := NewBufferFromBytes([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05}).SetStickyErrors(true)
+
+	a, _ := buf.ReadUint16BE()
+	b, _ := buf.ReadUint32LE()
+	c, _ := buf.ReadUint16BE()
+	d, _ := buf.ReadUint8()
+	require.Equal(t, uint16(0x0001), a)
+	require.Equal(t, uint32(0x05040302), b)
+	require.Equal(t, uint16(0), c)
+	require.Equal(t, uint8(0), d)
+
+	err := buf.Error()
+	var bufErr *BufferError
+	require.ErrorAs(t, err, &bufErr)
+	require.Equal(t, int64(6), bufErr.Offset)
+	require.Equal(t, "ReadUint16BE", bufErr.Op)
+	require.ErrorIs(t, err, io.EOF)
+
+	// Writes are no-ops once an error is recorded.
+	n, err := buf.WriteUint16BE(1)
+	require.Equal(t, 0, n)
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, int64(0), buf.Len())
+
+	_, err = buf.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+
+	buf.Reset()
+	require.NoError(t, buf.Error())
+
+	buf.SetMaxSize(3)
+	_, _ = buf.WriteUint16BE(0x0102)
+	_, _ = buf.WriteUint16BE(0x0304)
+	_, _ = buf.WriteUint8(5)
+	require.ErrorIs(t, buf.Error(), ErrBufferFull)
+	require.ErrorAs(t, buf.Error(), &bufErr)
+	require.Equal(t, "WriteUint16BE", bufErr.Op)
+	require.Equal(t, int64(2), bufErr.Offset)
+	require.Equal(t, int64(2), buf.Len())
+
+	buf = NewBufferFromBytes([]byte{0x1A, 0x05}).SetStickyErrors(true)
+	_, err = buf.ReadBCD(1)
+	require.ErrorIs(t, err, ErrInvalidBCD)
+	require.ErrorAs(t, err, &bufErr)
+	require.Equal(t, int64(0), bufErr.Offset)
+	require.Equal(t, int64(1), buf.Offset())
+	_, err = buf.ReadUvarint()
+	require.ErrorIs(t, err, ErrInvalidBCD)
+}
+
+func Test_BufferNonStickyErrors(t *testing.T) {
+	buf := NewBufferFromBytes([]byte{0x01})
+
+	_, err := buf.ReadUint16BE()
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+	require.NoError(t, buf.Error())
+
+	v, err := buf.ReadUint8()
+	require.NoError(t, err)
+	require.Equal(t, uint8(1), v)
+
+	// A bad BCD byte is consumed, as with Reader.ReadBCD.
+	buf = NewBufferFromBytes([]byte{0x1A, 0x05})
+	_, err = buf.ReadBCD(1)
+	require.ErrorIs(t, err, ErrInvalidBCD)
+	v, err = buf.ReadUint8()
+	require.NoError(t, err)
+	require.Equal(t, uint8(5), v)
+}
+
+func Test_BufferStickyPositionErrors(t *testing.T) {
+	var bufErr *BufferError
+
+	buf := NewBufferFromBytes([]byte{0x01}).SetStickyErrors(true)
+	require.Error(t, buf.UnreadByte())
+	require.ErrorAs(t, buf.Error(), &bufErr)
+	require.Equal(t, "UnreadByte", bufErr.Op)
+
+	buf = NewBufferFromBytes([]byte{0x01}).SetStickyErrors(true)
+	_, err := buf.Seek(2, io.SeekStart)
+	require.ErrorIs(t, err, ErrSeekOutOfRange)
+	require.ErrorAs(t, buf.Error(), &bufErr)
+	require.Equal(t, "Seek", bufErr.Op)
+	_, err = buf.ReadUint8()
+	require.ErrorIs(t, err, ErrSeekOutOfRange)
+
+	buf = NewBufferFromBytes([]byte{'a'}).SetStickyErrors(true)
+	_, _, err = buf.ReadRune()
+	require.NoError(t, err)
+	_, _, err = buf.ReadRune()
+	require.ErrorIs(t, err, io.EOF)
+	require.ErrorAs(t, buf.Error(), &bufErr)
+	require.Equal(t, "ReadRune", bufErr.Op)
+	require.Equal(t, int64(1), bufErr.Offset)
+}
+
+func Test_BufferReadFromMaxSize(t *testing.T) {
+	src := bytes.NewReader([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	buf := NewBufferFromBytes(nil).SetMaxSize(4)
+
+	n, err := buf.ReadFrom(src)
+	require.ErrorIs(t, err, ErrBufferFull)
+	require.Equal(t, int64(4), n)
+	require.Equal(t, 6, src.Len())
+
+	_, err = buf.Discard(3)
+	require.NoError(t, err)
+
+	n, err = buf.ReadFrom(src)
+	require.ErrorIs(t, err, ErrBufferFull)
+	require.Equal(t, int64(3), n)
+	require.Equal(t, 3, src.Len())
+
+	b, err := buf.ReadBytes(4)
+	require.NoError(t, err)
+	require.Equal(t, []byte{4, 5, 6, 7}, b)
+}
+
+func Test_BufferCompactKeepsReturnedSlices(t *testing.T) {
+	buf := NewBufferFromBytes(make([]byte, 0, 64)).SetCompactThreshold(1)
+	_, err := buf.WriteString("ab\ncd\n")
+	require.NoError(t, err)
+
+	line, err := buf.ReadBytesTill('\n')
+	require.NoError(t, err)
+	require.Equal(t, []byte("ab\n"), line)
+
+	_, err = buf.WriteString("xyz")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), buf.Offset()-int64(buf.cursor.offset))
+	require.Equal(t, []byte("ab\n"), line)
+
+	line, err = buf.ReadBytesTill('\n')
+	require.NoError(t, err)
+	buf.Compact()
+	_, err = buf.WriteString("123456")
+	require.NoError(t, err)
+	require.Equal(t, []byte("cd\n"), line)
+}
@@ -0,0 +1,77 @@
+package gocodec
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrInvalidCheckpoint is returned when a checkpoint is rolled back or
+// committed after it was already released.
+var ErrInvalidCheckpoint = errors.New("binstruct: invalid checkpoint")
+
+// Checkpoint is a read position of a Buffer saved by Mark.
+type Checkpoint struct {
+	offset int64
+	depth  int
+}
+
+// Offset returns the read position saved by the checkpoint.
+func (cp Checkpoint) Offset() int64 {
+	return cp.offset
+}
+
+// Mark saves the current read position. The checkpoint must be released
+// with Rollback or Commit. Checkpoints nest: releasing one also releases
+// every checkpoint taken after it.
+func (buf *Buffer) Mark() Checkpoint {
+	buf.marks = append(buf.marks, buf.Offset())
+	return Checkpoint{offset: buf.Offset(), depth: len(buf.marks)}
+}
+
+// Rollback moves the read position back to cp and releases it.
+func (buf *Buffer) Rollback(cp Checkpoint) error {
+	if err := buf.release(cp); err != nil {
+		return err
+	}
+
+	_, err := buf.Seek(cp.offset, io.SeekStart)
+	return err
+}
+
+// Commit releases cp and keeps the current read position.
+func (buf *Buffer) Commit(cp Checkpoint) error {
+	return buf.release(cp)
+}
+
+func (buf *Buffer) release(cp Checkpoint) error {
+	if cp.depth < 1 || cp.depth > len(buf.marks) || buf.marks[cp.depth-1] != cp.offset {
+		return ErrInvalidCheckpoint
+	}
+
+	buf.marks = buf.marks[:cp.depth-1]
+	return nil
+}
+
+// TryParse runs fn and rolls the read position back to where it was if fn
+// fails with io.EOF or io.ErrUnexpectedEOF, so a partially received frame
+// can be parsed again once more data is written. A sticky error recorded
+// by such a failure is cleared as well. On other errors and on success the
+// bytes read by fn stay consumed.
+func (buf *Buffer) TryParse(fn func(*Buffer) error) error {
+	cp := buf.Mark()
+	sticky := buf.err
+
+	err := fn(buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		if rerr := buf.Rollback(cp); rerr != nil {
+			return rerr
+		}
+		buf.err = sticky
+		return err
+	}
+
+	if cerr := buf.Commit(cp); cerr != nil && err == nil {
+		return cerr
+	}
+	return err
+}
@@ -0,0 +1,155 @@
+package gocodec
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Checkpoint(t *testing.T) {
+	var buf Buffer
+	_, err := buf.Write([]byte{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+
+	outer := buf.Mark()
+	_, err = buf.ReadUint16BE()
+	require.NoError(t, err)
+
+	inner := buf.Mark()
+	require.Equal(t, int64(2), inner.Offset())
+	_, err = buf.ReadUint16BE()
+	require.NoError(t, err)
+
+	require.NoError(t, buf.Rollback(inner))
+	require.Equal(t, int64(2), buf.Offset())
+	require.ErrorIs(t, buf.Rollback(inner), ErrInvalidCheckpoint)
+
+	inner = buf.Mark()
+	_, err = buf.ReadUint8()
+	require.NoError(t, err)
+	require.NoError(t, buf.Commit(inner))
+	require.Equal(t, int64(3), buf.Offset())
+
+	// Compaction keeps the bytes after the oldest checkpoint.
+	buf.Compact()
+	require.Equal(t, int64(6), buf.Len()+buf.Offset())
+	require.NoError(t, buf.Rollback(outer))
+	v, err := buf.ReadUint8()
+	require.NoError(t, err)
+	require.Equal(t, uint8(1), v)
+
+	buf.Compact()
+	require.Equal(t, []byte{2, 3, 4, 5, 6}, buf.cursor.buffer)
+
+	// Releasing an outer checkpoint releases the inner ones too.
+	outer = buf.Mark()
+	inner = buf.Mark()
+	require.NoError(t, buf.Commit(outer))
+	require.ErrorIs(t, buf.Commit(inner), ErrInvalidCheckpoint)
+
+	buf.Mark()
+	buf.Reset()
+	require.Empty(t, buf.marks)
+}
+
+func Test_TryParse(t *testing.T) {
+	type frame struct {
+		Len     uint8
+		Payload []byte `bin:"len:Len"`
+	}
+
+	parse := func(frames *[]frame) func(*Buffer) error {
+		return func(buf *Buffer) error {
+			var f frame
+			if err := buf.Reader(binary.BigEndian).Unmarshal(&f); err != nil {
+				return err
+			}
+			*frames = append(*frames, f)
+			return nil
+		}
+	}
+
+	buf := NewBufferFromBytes(nil).SetCompactThreshold(1)
+	stream := []byte{3, 'a', 'b', 'c', 2, 'd', 'e', 0, 1, 'f'}
+
+	var frames []frame
+	for _, c := range stream {
+		require.NoError(t, buf.WriteByte(c))
+		for {
+			err := buf.TryParse(parse(&frames))
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			require.NoError(t, err)
+		}
+	}
+
+	require.Equal(t, []frame{
+		{3, []byte("abc")},
+		{2, []byte("de")},
+		{0, []byte{}},
+		{1, []byte("f")},
+	}, frames)
+	require.Equal(t, int64(0), buf.Len())
+	require.Empty(t, buf.marks)
+
+	buf.SetStickyErrors(true)
+	err := buf.TryParse(func(buf *Buffer) error {
+		_, err := buf.ReadUint32BE()
+		return err
+	})
+	require.ErrorIs(t, err, io.EOF)
+	require.NoError(t, buf.Error())
+
+	errBad := errors.New("bad frame")
+	_, err = buf.Write([]byte{9, 9})
+	require.NoError(t, err)
+	err = buf.TryParse(func(buf *Buffer) error {
+		_, _ = buf.ReadUint8()
+		return errBad
+	})
+	require.ErrorIs(t, err, errBad)
+	require.Equal(t, int64(1), buf.Len())
+}
+
+func Test_CompactWithOpenMark(t *testing.T) {
+	var buf Buffer
+	_, err := buf.Write([]byte{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+
+	_, err = buf.ReadUint8()
+	require.NoError(t, err)
+	cp := buf.Mark()
+	v16, err := buf.ReadUint16BE()
+	require.NoError(t, err)
+	require.Equal(t, uint16(0x0203), v16)
+
+	buf.Compact()
+	require.Equal(t, []byte{2, 3, 4, 5, 6}, buf.cursor.buffer)
+	require.Equal(t, int64(3), buf.Offset())
+
+	v, err := buf.ReadUint8()
+	require.NoError(t, err)
+	require.Equal(t, uint8(4), v)
+
+	_, err = buf.Discard(1)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), buf.Offset())
+
+	buf.SetCompactThreshold(1)
+	_, err = buf.WriteUint8(7)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), buf.Offset())
+
+	require.NoError(t, buf.Rollback(cp))
+	require.Equal(t, int64(1), buf.Offset())
+	v, err = buf.ReadUint8()
+	require.NoError(t, err)
+	require.Equal(t, uint8(2), v)
+
+	buf.Compact()
+	require.Equal(t, []byte{3, 4, 5, 6, 7}, buf.cursor.buffer)
+}
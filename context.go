@@ -0,0 +1,114 @@
+package gocodec
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// UnmarshalContext is like Unmarshal but stops with ctx.Err(), wrapped
+// in a DecodeError locating the field, once ctx is done. It reads from
+// memory only, so unlike DecodeContext it never touches read deadlines.
+func UnmarshalContext(ctx context.Context, data []byte, order binary.ByteOrder, v interface{}, opts ...Option) error {
+	opts = append(opts[:len(opts):len(opts)], withContext(ctx))
+	r := NewReaderFromBytes(data, order, false, opts...)
+	err := r.Unmarshal(v)
+
+	return contextError(ctx, err, offset(r))
+}
+
+// DecodeContext is like Decode but stops once ctx is done. The context is
+// checked before every field, array element and read. Reads blocked in an
+// input implementing SetReadDeadline (such as net.Conn or os.File) are
+// interrupted by moving its read deadline.
+//
+// The deadline can't be read back, so if ctx is done while DecodeContext
+// runs, the input is left with no read deadline when it returns, even if
+// the caller had set one before. Set it again before further reads if it
+// is needed. If ctx is not done, the deadline is never touched.
+//
+// A cancelled decode returns ctx.Err() wrapped in a DecodeError, whose
+// Path and Offset tell how far the decode got.
+func (dec *Decoder) DecodeContext(ctx context.Context, v interface{}) error {
+	stop := interruptReads(ctx, dec.r)
+	r := dec.reader(withContext(ctx))
+	err := dec.decode(r, v)
+	stop()
+
+	return contextError(ctx, err, offset(r))
+}
+
+func withContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// contextErr returns the error of ctx, if any.
+func contextErr(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+
+	return ctx.Err()
+}
+
+// contextError replaces the cause of a decode error by ctx.Err() when the
+// decode failed because ctx is done, e.g. with an expired read deadline.
+// Other errors are wrapped in a DecodeError at offset, the position the
+// decode reached.
+func contextError(ctx context.Context, err error, offset int64) error {
+	ctxErr := ctx.Err()
+	if err == nil || err == io.EOF || ctxErr == nil || errors.Is(err, ctxErr) {
+		return err
+	}
+
+	if e, ok := err.(*DecodeError); ok {
+		canceled := *e
+		canceled.Err = ctxErr
+		return &canceled
+	}
+
+	return &DecodeError{Offset: offset, Err: ctxErr}
+}
+
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// interruptReads makes blocked reads of r return once ctx is done, if r
+// supports read deadlines. The returned func must be called at the end of
+// the decode.
+func interruptReads(ctx context.Context, r io.Reader) (stop func()) {
+	if s, ok := r.(*streamReader); ok {
+		r = s.src
+	}
+
+	d, ok := r.(readDeadliner)
+	if !ok || ctx.Done() == nil {
+		return func() {}
+	}
+
+	var mu sync.Mutex
+	stopAfter := context.AfterFunc(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		d.SetReadDeadline(time.Unix(1, 0))
+	})
+
+	return func() {
+		// If the deadline was not moved yet, it is left as the caller set it.
+		if stopAfter() {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		d.SetReadDeadline(time.Time{})
+	}
+}
@@ -0,0 +1,104 @@
+package gocodec
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UnmarshalContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var v struct {
+		A uint8
+	}
+
+	err := UnmarshalContext(ctx, []byte{0x01}, binary.BigEndian, &v)
+	require.ErrorIs(t, err, context.Canceled)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "A", decodeErr.Path)
+	require.Equal(t, uint8(0), v.A)
+}
+
+func Test_DecodeContextBlockedRead(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go server.Write([]byte{0x01, 0x02})
+
+	var v struct {
+		A uint16
+		B uint32
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := NewStreamDecoder(client, binary.BigEndian).DecodeContext(ctx, &v)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, uint16(0x0102), v.A)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "B", decodeErr.Path)
+	require.Equal(t, int64(2), decodeErr.Offset)
+
+	// The read deadline is cleared after the interrupted decode.
+	go server.Write([]byte{0x03})
+	b := make([]byte, 1)
+	_, err = client.Read(b)
+	require.NoError(t, err)
+	require.Equal(t, byte(0x03), b[0])
+}
+
+type deadlineRecorder struct {
+	*bytes.Reader
+	deadlines []time.Time
+}
+
+func (d *deadlineRecorder) SetReadDeadline(t time.Time) error {
+	d.deadlines = append(d.deadlines, t)
+	return nil
+}
+
+func Test_DecodeContextKeepsDeadline(t *testing.T) {
+	r := &deadlineRecorder{Reader: bytes.NewReader([]byte{0x01, 0x02})}
+
+	var v struct {
+		A uint16
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := NewDecoder(r, binary.BigEndian).DecodeContext(ctx, &v)
+	require.NoError(t, err)
+	require.Empty(t, r.deadlines)
+}
+
+func Test_ContextErrorOutsideField(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var v struct {
+		A uint8
+	}
+
+	err := UnmarshalContext(ctx, []byte{0x01}, binary.BigEndian, v)
+	require.ErrorIs(t, err, context.Canceled)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "", decodeErr.Path)
+	require.Equal(t, int64(0), decodeErr.Offset)
+	require.Equal(t, "decode stopped at offset 0: context canceled", err.Error())
+}
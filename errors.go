@@ -2,7 +2,9 @@ package gocodec
 
 import (
 	"errors"
+	"fmt"
 	"io"
+	"reflect"
 )
 
 // Deprecated: use errors.Is(err, io.EOF)
@@ -16,3 +18,53 @@ func IsEOF(err error) bool {
 func IsUnexpectedEOF(err error) bool {
 	return errors.Is(err, io.ErrUnexpectedEOF)
 }
+
+// A DecodeError describes a failure to decode a field. Path is the full
+// field path (e.g. "Header.Items[3].Name"), Offset is the stream offset
+// where the failing read started and Type is the Go type of the field.
+// A cancelled decode outside any field has an empty Path and no Type.
+type DecodeError struct {
+	Path   string
+	Offset int64
+	Type   reflect.Type
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("decode stopped at offset %d: %v", e.Offset, e.Err)
+	}
+
+	return `failed set value to field "` + e.Path + `": ` + e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// newDecodeError wraps err into a DecodeError. Errors which are already
+// a DecodeError are returned as is, so the deepest path is kept.
+func newDecodeError(path string, offset int64, typ reflect.Type, err error) error {
+	if _, ok := err.(*DecodeError); ok {
+		return err
+	}
+
+	return &DecodeError{
+		Path:   path,
+		Offset: offset,
+		Type:   typ,
+		Err:    err,
+	}
+}
+
+// unexpectedEOF turns an io.EOF decode error into one matching
+// io.ErrUnexpectedEOF, for EOFs in the middle of a value.
+func unexpectedEOF(err error) error {
+	if e, ok := err.(*DecodeError); ok && e.Err == io.EOF {
+		unexpected := *e
+		unexpected.Err = io.ErrUnexpectedEOF
+		return &unexpected
+	}
+
+	return fmt.Errorf("%v: %w", err, io.ErrUnexpectedEOF)
+}
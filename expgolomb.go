@@ -0,0 +1,178 @@
+package gocodec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"reflect"
+)
+
+const (
+	tagTypeBits = "bits"
+	tagTypeUE   = "ue"
+	tagTypeSE   = "se"
+)
+
+var (
+	// ErrExpGolomb is returned for Exp-Golomb codes which don't fit in 64 bits.
+	ErrExpGolomb = errors.New("binstruct: Exp-Golomb code overflows 64 bits")
+)
+
+// ReadUE reads an unsigned Exp-Golomb code, ue(v) in H.264 and H.265.
+func (br *BitReader) ReadUE() (uint64, error) {
+	zeros := 0
+	for {
+		bit, err := br.ReadBit()
+		if err != nil {
+			return 0, eofInValue(err, zeros > 0)
+		}
+		if bit == 1 {
+			break
+		}
+
+		zeros++
+		if zeros > 63 {
+			return 0, ErrExpGolomb
+		}
+	}
+
+	v := uint64(1)
+	for i := 0; i < zeros; i++ {
+		bit, err := br.ReadBit()
+		if err != nil {
+			return 0, eofInValue(err, true)
+		}
+		v = v<<1 | uint64(bit)
+	}
+
+	return v - 1, nil
+}
+
+// ReadSE reads a signed Exp-Golomb code, se(v) in H.264 and H.265.
+func (br *BitReader) ReadSE() (int64, error) {
+	k, err := br.ReadUE()
+	if err != nil {
+		return 0, err
+	}
+
+	if k&1 == 1 {
+		return int64(k/2 + 1), nil
+	}
+	return -int64(k / 2), nil
+}
+
+// WriteUE writes v as an unsigned Exp-Golomb code.
+func (bw *BitWriter) WriteUE(v uint64) error {
+	if v == math.MaxUint64 {
+		return ErrExpGolomb
+	}
+
+	v++
+	n := bits.Len64(v)
+	for i := 0; i < n-1; i++ {
+		if err := bw.WriteBit(0); err != nil {
+			return err
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		if err := bw.WriteBit(uint8(v >> i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteSE writes v as a signed Exp-Golomb code.
+func (bw *BitWriter) WriteSE(v int64) error {
+	if v > 0 {
+		return bw.WriteUE(uint64(v)*2 - 1)
+	}
+	if v == math.MinInt64 {
+		return ErrExpGolomb
+	}
+	return bw.WriteUE(uint64(-v) * 2)
+}
+
+// bitField reports whether data decodes a bit field. Runs of consecutive
+// bit fields share one BitReader, so they don't have to be byte aligned.
+func (data *fieldReadData) bitField() bool {
+	return data.Bits != nil || data.ExpGolomb != "" ||
+		(data.ElemFieldData != nil && data.ElemFieldData.bitField())
+}
+
+// bitReader returns the BitReader of the current run of bit fields,
+// starting a new run if there is none.
+func (u *unmarshal) bitReader() *BitReader {
+	if u.state.bits == nil {
+		u.state.bits = NewBitReader(u.r, MSBFirst)
+	}
+	return u.state.bits
+}
+
+func (u *unmarshal) decodeBits(fieldValue reflect.Value, fieldData *fieldReadData) error {
+	br := u.bitReader()
+
+	var value uint64
+	var err error
+	switch fieldData.ExpGolomb {
+	case tagTypeUE:
+		value, err = br.ReadUE()
+	case tagTypeSE:
+		var v int64
+		v, err = br.ReadSE()
+		value = uint64(v)
+	default:
+		n := *fieldData.Bits
+		if n < 0 || n > 64 {
+			return fmt.Errorf("%w: %d", ErrBitCount, n)
+		}
+		value, err = br.ReadBits(int(n))
+		if n > 0 && n < 64 && fieldValue.Kind() >= reflect.Int && fieldValue.Kind() <= reflect.Int64 {
+			shift := 64 - n
+			value = uint64(int64(value<<shift) >> shift)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	switch fieldValue.Kind() {
+	case reflect.Bool:
+		if fieldData.ExpGolomb != "" {
+			return errors.New(fieldData.ExpGolomb + " tag needs an integer field")
+		}
+		if fieldValue.CanSet() {
+			fieldValue.SetBool(value != 0)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if fieldData.ExpGolomb == tagTypeSE {
+			return errors.New("se tag needs a signed integer field")
+		}
+		if fieldValue.OverflowUint(value) {
+			return fmt.Errorf("value %d overflows %s", value, fieldValue.Type())
+		}
+		if fieldValue.CanSet() {
+			fieldValue.SetUint(value)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v := int64(value)
+		if fieldData.ExpGolomb == tagTypeUE && value > math.MaxInt64 {
+			return fmt.Errorf("value %d overflows %s", value, fieldValue.Type())
+		}
+		if fieldValue.OverflowInt(v) {
+			return fmt.Errorf("value %d overflows %s", v, fieldValue.Type())
+		}
+		if fieldValue.CanSet() {
+			fieldValue.SetInt(v)
+		}
+
+	default:
+		return errors.New("bits, ue and se tags need an integer or bool field")
+	}
+
+	return nil
+}
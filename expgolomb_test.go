@@ -0,0 +1,179 @@
+package gocodec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExpGolomb(t *testing.T) {
+	// ue: 0 -> 1, 1 -> 010, 2 -> 011, 3 -> 00100
+	c := NewCursor([]byte{0b1_010_011_0, 0b0100_0000})
+	br := NewBitReaderFromCursor(&c, MSBFirst)
+	for want := uint64(0); want < 4; want++ {
+		v, err := br.ReadUE()
+		require.NoError(t, err)
+		require.Equal(t, want, v)
+	}
+
+	_, err := br.ReadUE()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	ues := []uint64{0, 1, 2, 3, 255, 1 << 32, math.MaxUint64 - 1}
+	ses := []int64{0, 1, -1, 2, -2, 1000, -1000, math.MaxInt64, math.MinInt64 + 1}
+
+	bw := NewBitWriter(MSBFirst)
+	for _, v := range ues {
+		require.NoError(t, bw.WriteUE(v))
+	}
+	for _, v := range ses {
+		require.NoError(t, bw.WriteSE(v))
+	}
+	require.NoError(t, bw.AlignZero())
+
+	c = NewCursor(bw.Bytes())
+	br = NewBitReaderFromCursor(&c, MSBFirst)
+	for _, want := range ues {
+		v, err := br.ReadUE()
+		require.NoError(t, err)
+		require.Equal(t, want, v)
+	}
+	for _, want := range ses {
+		v, err := br.ReadSE()
+		require.NoError(t, err)
+		require.Equal(t, want, v)
+	}
+
+	require.ErrorIs(t, bw.WriteUE(math.MaxUint64), ErrExpGolomb)
+	require.ErrorIs(t, bw.WriteSE(math.MinInt64), ErrExpGolomb)
+
+	c = NewCursor(make([]byte, 9))
+	_, err = NewBitReaderFromCursor(&c, MSBFirst).ReadUE()
+	require.ErrorIs(t, err, ErrExpGolomb)
+}
+
+type testSPS struct {
+	NALHeader   uint8
+	ProfileIdc  uint8
+	Constraints [6]bool `bin:"[bits:1]"`
+	Reserved    uint8   `bin:"bits:2"`
+	LevelIdc    uint8
+	ID          uint32 `bin:"ue"`
+	Log2MaxFrm  uint32 `bin:"ue"`
+	Frame       struct {
+		Cropping bool  `bin:"bits:1"`
+		Offset   int32 `bin:"se"`
+		Delta    int8  `bin:"bits:4"`
+	}
+	Trailer uint8
+}
+
+func Test_ExpGolombTags(t *testing.T) {
+	bw := NewBitWriter(MSBFirst)
+	require.NoError(t, bw.WriteBits(0x67, 8))
+	require.NoError(t, bw.WriteBits(100, 8))
+	require.NoError(t, bw.WriteBits(0b101000, 6))
+	require.NoError(t, bw.WriteBits(0, 2))
+	require.NoError(t, bw.WriteBits(0x00, 8))
+	require.NoError(t, bw.WriteUE(0))
+	require.NoError(t, bw.WriteUE(0))
+	require.NoError(t, bw.WriteBit(1))
+	require.NoError(t, bw.WriteSE(-7))
+	require.NoError(t, bw.WriteBits(0b1110, 4))
+	require.NoError(t, bw.AlignOne())
+	require.NoError(t, bw.WriteBits(0x80, 8))
+
+	var v testSPS
+	err := Unmarshal(bw.Bytes(), binary.BigEndian, &v)
+	require.NoError(t, err)
+	require.Equal(t, uint8(100), v.ProfileIdc)
+	require.Equal(t, [6]bool{true, false, true}, v.Constraints)
+	require.Equal(t, uint8(0), v.LevelIdc)
+	require.Equal(t, uint32(0), v.ID)
+	require.Equal(t, uint32(0), v.Log2MaxFrm)
+	require.True(t, v.Frame.Cropping)
+	require.Equal(t, int32(-7), v.Frame.Offset)
+	require.Equal(t, int8(-2), v.Frame.Delta)
+	require.Equal(t, uint8(0x80), v.Trailer)
+
+	var bad struct {
+		U uint8 `bin:"se"`
+	}
+	err = Unmarshal([]byte{0xFF}, binary.BigEndian, &bad)
+	require.Error(t, err)
+
+	var overflow struct {
+		U uint8 `bin:"ue"`
+	}
+	err = Unmarshal([]byte{0x00, 0x80, 0x80}, binary.BigEndian, &overflow)
+	require.Error(t, err)
+}
+
+func Test_ExpGolombByteSlice(t *testing.T) {
+	var v struct {
+		N  uint8
+		Ns []uint8 `bin:"len:N,[bits:4]"`
+		Us []uint8 `bin:"len:N,[ue]"`
+	}
+
+	// 0b1 0b010 are ue 0 and 1, padded to a byte.
+	err := Unmarshal([]byte{0x02, 0x12, 0xA0}, binary.BigEndian, &v)
+	require.NoError(t, err)
+	require.Equal(t, []uint8{1, 2}, v.Ns)
+	require.Equal(t, []uint8{0, 1}, v.Us)
+}
+
+func Test_RBSP(t *testing.T) {
+	escaped := []byte{0x67, 0x00, 0x00, 0x03, 0x01, 0x00, 0x00, 0x03, 0x00, 0x00, 0x03}
+	want := []byte{0x67, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}
+	require.Equal(t, want, UnescapeRBSP(escaped))
+
+	got, err := io.ReadAll(NewRBSPReader(bytes.NewReader(escaped)))
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	got, err = io.ReadAll(NewRBSPReader(io.MultiReader(bytes.NewReader(escaped[:2]), bytes.NewReader(escaped[2:]))))
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	var v struct {
+		Header uint8
+		A      uint16
+		B      uint8
+	}
+	err = NewStreamReader(NewRBSPReader(bytes.NewReader(escaped)), binary.BigEndian).Unmarshal(&v)
+	require.NoError(t, err)
+	require.Equal(t, uint16(0), v.A)
+	require.Equal(t, uint8(1), v.B)
+}
+
+func Test_RBSPReaderShortRead(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	go pw.Write([]byte{0x67, 0x00, 0x00, 0x03, 0x01})
+
+	done := make(chan error, 1)
+	var v struct {
+		Header uint8
+		A      uint16
+		B      uint8
+	}
+	go func() {
+		done <- NewStreamReader(NewRBSPReader(pr), binary.BigEndian).Unmarshal(&v)
+	}()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+		require.Equal(t, uint8(0x67), v.Header)
+		require.Equal(t, uint8(1), v.B)
+	case <-time.After(time.Second):
+		t.Fatal("read blocked on a live stream with enough data")
+	}
+}
@@ -0,0 +1,219 @@
+package gocodec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	tagTypeFixed         = "fixed"
+	tagTypeFixedUnsigned = "ufixed"
+	tagTypeQ             = "q"
+	tagTypeQUnsigned     = "uq"
+)
+
+var (
+	// ErrFixedOverflow is returned when a value is out of the range of
+	// a non-saturating fixed-point format.
+	ErrFixedOverflow = errors.New("binstruct: value out of fixed-point range")
+)
+
+// RoundingMode tells how FixedPoint.ToRaw rounds values which are not
+// a multiple of the resolution.
+type RoundingMode int
+
+const (
+	// RoundNearest rounds half away from zero.
+	RoundNearest RoundingMode = iota
+	// RoundNearestEven rounds half to even.
+	RoundNearestEven
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+	// RoundCeil rounds toward positive infinity.
+	RoundCeil
+	// RoundTrunc rounds toward zero.
+	RoundTrunc
+)
+
+// FixedPoint describes a binary fixed-point format with IntBits integer
+// bits, including the sign bit when Signed, and FracBits fractional bits.
+// For example Q15 is {IntBits: 1, FracBits: 15, Signed: true} and
+// Q16.16 is {IntBits: 16, FracBits: 16, Signed: true}.
+//
+// In struct tags the formats are written as `fixed:I.F` and `ufixed:I.F`
+// for signed and unsigned I.F, `q:F` for signed 1.F and `uq:F` for
+// unsigned 0.F. They decode into float32 and float64 fields from
+// Size() bytes, a `len` tag sets another container width.
+type FixedPoint struct {
+	IntBits  int
+	FracBits int
+	Signed   bool
+
+	// Rounding and Saturate control ToRaw. Out of range values are
+	// clamped when Saturate is set, otherwise ErrFixedOverflow is returned.
+	Rounding RoundingMode
+	Saturate bool
+}
+
+// Bits returns the total number of bits of the format.
+func (f FixedPoint) Bits() int {
+	return f.IntBits + f.FracBits
+}
+
+// Size returns the number of bytes needed to store the format.
+func (f FixedPoint) Size() int {
+	return (f.Bits() + 7) / 8
+}
+
+func (f FixedPoint) validate() error {
+	if f.IntBits < 0 || f.FracBits < 0 || f.Bits() == 0 || f.Bits() > 64 {
+		return fmt.Errorf("binstruct: invalid fixed-point format %d.%d", f.IntBits, f.FracBits)
+	}
+
+	if f.Signed && f.IntBits == 0 {
+		return fmt.Errorf("binstruct: signed fixed-point format %d.%d needs a sign bit", f.IntBits, f.FracBits)
+	}
+
+	return nil
+}
+
+// FromRaw converts the raw integer of the format into a float. Bits
+// above Bits() are ignored, signed values are sign extended.
+func (f FixedPoint) FromRaw(raw uint64) float64 {
+	bits := f.Bits()
+	if bits < 64 {
+		raw &= 1<<bits - 1
+	}
+
+	if f.Signed {
+		i := int64(raw<<(64-bits)) >> (64 - bits)
+		return math.Ldexp(float64(i), -f.FracBits)
+	}
+
+	return math.Ldexp(float64(raw), -f.FracBits)
+}
+
+// ToRaw converts v into the raw integer of the format, rounded with
+// f.Rounding. Negative values are returned in two's complement over
+// Bits() bits.
+func (f FixedPoint) ToRaw(v float64) (uint64, error) {
+	if math.IsNaN(v) {
+		return 0, fmt.Errorf("%w: NaN", ErrFixedOverflow)
+	}
+
+	scaled := math.Ldexp(v, f.FracBits)
+	switch f.Rounding {
+	case RoundNearestEven:
+		scaled = math.RoundToEven(scaled)
+	case RoundFloor:
+		scaled = math.Floor(scaled)
+	case RoundCeil:
+		scaled = math.Ceil(scaled)
+	case RoundTrunc:
+		scaled = math.Trunc(scaled)
+	default:
+		scaled = math.Round(scaled)
+	}
+
+	bits := f.Bits()
+	mask := uint64(math.MaxUint64)
+	if bits < 64 {
+		mask = 1<<bits - 1
+	}
+
+	// Range is [lo, hi), both bounds are exact powers of two.
+	lo, hi := 0.0, math.Ldexp(1, bits)
+	if f.Signed {
+		lo, hi = -math.Ldexp(1, bits-1), math.Ldexp(1, bits-1)
+	}
+
+	if scaled < lo || scaled >= hi {
+		if !f.Saturate {
+			return 0, fmt.Errorf("%w: %v", ErrFixedOverflow, v)
+		}
+
+		switch {
+		case scaled < lo && f.Signed:
+			return 1 << (bits - 1), nil
+		case scaled < lo:
+			return 0, nil
+		case f.Signed:
+			return 1<<(bits-1) - 1, nil
+		default:
+			return mask, nil
+		}
+	}
+
+	if f.Signed {
+		return uint64(int64(scaled)) & mask, nil
+	}
+
+	return uint64(scaled), nil
+}
+
+func parseFixedTag(t tag) (*FixedPoint, error) {
+	var f FixedPoint
+	var err error
+
+	switch t.Type {
+	case tagTypeFixed, tagTypeFixedUnsigned:
+		f.Signed = t.Type == tagTypeFixed
+		intBits, fracBits, ok := strings.Cut(t.Value, ".")
+		if !ok {
+			return nil, errors.New(`fixed-point tag "` + t.Type + ":" + t.Value + `" must be I.F`)
+		}
+		if f.IntBits, err = strconv.Atoi(intBits); err != nil {
+			return nil, fmt.Errorf("parse fixed-point integer bits: %w", err)
+		}
+		if f.FracBits, err = strconv.Atoi(fracBits); err != nil {
+			return nil, fmt.Errorf("parse fixed-point fractional bits: %w", err)
+		}
+	case tagTypeQ, tagTypeQUnsigned:
+		f.Signed = t.Type == tagTypeQ
+		if f.Signed {
+			f.IntBits = 1
+		}
+		if f.FracBits, err = strconv.Atoi(t.Value); err != nil {
+			return nil, fmt.Errorf("parse fixed-point fractional bits: %w", err)
+		}
+	}
+
+	if err = f.validate(); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func (u *unmarshal) decodeFixed(r Reader, fieldValue reflect.Value, fieldData *fieldReadData) error {
+	f := fieldData.Fixed
+
+	switch fieldValue.Kind() {
+	case reflect.Float32, reflect.Float64:
+	default:
+		return errors.New("fixed-point tag needs a float32 or float64 field")
+	}
+
+	size := f.Size()
+	if fieldData.Length != nil {
+		size = int(*fieldData.Length)
+		if size*8 < f.Bits() {
+			return fmt.Errorf("len %d is too small for %d bits fixed-point value", size, f.Bits())
+		}
+	}
+
+	raw, err := r.ReadUintX(size)
+	if err != nil {
+		return err
+	}
+
+	if fieldValue.CanSet() {
+		fieldValue.SetFloat(f.FromRaw(raw))
+	}
+
+	return nil
+}
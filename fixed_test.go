@@ -0,0 +1,124 @@
+package gocodec
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FixedTags(t *testing.T) {
+	data := []byte{
+		0x40, 0x00, // q:15 = 0.5
+		0x80, 0x00, // q:15 = -1
+		0xFF, 0xFE, 0x80, 0x00, // fixed:16.16 = -1.5
+		0x01, 0x80, // ufixed:8.8 = 1.5
+		0x00, 0x00, 0x00, 0x40, // q:15,len:4 in a 32-bit container = 0.001953125
+		0x00, 0x80, 0xFF, 0xC0, // [q:15] = 1/256, -1/512
+	}
+
+	var v struct {
+		Q15       float64    `bin:"q:15"`
+		Q15Min    float32    `bin:"q:15"`
+		Q16_16    float64    `bin:"fixed:16.16"`
+		UQ8_8     float64    `bin:"ufixed:8.8"`
+		Container float64    `bin:"q:15,len:4"`
+		Samples   [2]float32 `bin:"[q:15]"`
+	}
+
+	err := UnmarshalBE(data, &v)
+	require.NoError(t, err)
+	require.Equal(t, 0.5, v.Q15)
+	require.Equal(t, float32(-1), v.Q15Min)
+	require.Equal(t, -1.5, v.Q16_16)
+	require.Equal(t, 1.5, v.UQ8_8)
+	require.Equal(t, 64.0/32768, v.Container)
+	require.Equal(t, [2]float32{1.0 / 256, -1.0 / 512}, v.Samples)
+}
+
+func Test_FixedTagErrors(t *testing.T) {
+	var notFloat struct {
+		V int16 `bin:"q:15"`
+	}
+	err := UnmarshalBE([]byte{0x00, 0x00}, &notFloat)
+	require.EqualError(t, err, `failed set value to field "V": fixed-point tag needs a float32 or float64 field`)
+
+	var badFormat struct {
+		V float64 `bin:"fixed:16"`
+	}
+	err = UnmarshalBE([]byte{0x00, 0x00}, &badFormat)
+	require.Error(t, err)
+
+	var tooWide struct {
+		V float64 `bin:"fixed:40.40"`
+	}
+	err = UnmarshalBE([]byte{0x00, 0x00}, &tooWide)
+	require.Error(t, err)
+}
+
+func Test_FixedPointRoundTrip(t *testing.T) {
+	q15 := FixedPoint{IntBits: 1, FracBits: 15, Signed: true}
+
+	for _, raw := range []uint64{0x0000, 0x0001, 0x4000, 0x7FFF, 0x8000, 0xFFFF} {
+		v := q15.FromRaw(raw)
+		back, err := q15.ToRaw(v)
+		require.NoError(t, err)
+		require.Equal(t, raw, back)
+	}
+
+	q16 := FixedPoint{IntBits: 16, FracBits: 16, Signed: true}
+	raw, err := q16.ToRaw(-1.5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0xFFFE8000), raw)
+}
+
+func Test_FixedPointRounding(t *testing.T) {
+	f := FixedPoint{IntBits: 8, FracBits: 0, Signed: true}
+	tests := []struct {
+		mode RoundingMode
+		in   float64
+		want uint64
+	}{
+		{RoundNearest, 2.5, 3},
+		{RoundNearest, -2.5, 0xFD},
+		{RoundNearestEven, 2.5, 2},
+		{RoundFloor, -2.5, 0xFD},
+		{RoundCeil, 2.1, 3},
+		{RoundTrunc, -2.9, 0xFE},
+	}
+
+	for _, tt := range tests {
+		f.Rounding = tt.mode
+		raw, err := f.ToRaw(tt.in)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, raw, "mode %d, value %v", tt.mode, tt.in)
+	}
+}
+
+func Test_FixedPointSaturation(t *testing.T) {
+	q15 := FixedPoint{IntBits: 1, FracBits: 15, Signed: true}
+
+	_, err := q15.ToRaw(1.0)
+	require.ErrorIs(t, err, ErrFixedOverflow)
+	_, err = q15.ToRaw(math.NaN())
+	require.ErrorIs(t, err, ErrFixedOverflow)
+
+	q15.Saturate = true
+	raw, err := q15.ToRaw(1.0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x7FFF), raw)
+
+	raw, err = q15.ToRaw(-3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x8000), raw)
+
+	u64 := FixedPoint{IntBits: 64, Saturate: true}
+	raw, err = u64.ToRaw(math.Inf(1))
+	require.NoError(t, err)
+	require.Equal(t, uint64(math.MaxUint64), raw)
+
+	s64 := FixedPoint{IntBits: 64, Signed: true, Saturate: true}
+	raw, err = s64.ToRaw(math.Inf(-1))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1)<<63, raw)
+}
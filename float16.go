@@ -0,0 +1,136 @@
+package gocodec
+
+import (
+	"errors"
+	"math"
+	"reflect"
+)
+
+// Float16bits returns the IEEE 754 half-precision representation of f,
+// rounded to nearest even. Values too large for half precision become
+// infinities, values too small become subnormals or zeros, and NaNs
+// stay NaNs.
+func Float16bits(f float32) uint16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int32(b>>23) & 0xFF
+	mant := b & 0x7FFFFF
+
+	if exp == 0xFF {
+		if mant == 0 {
+			return sign | 0x7C00
+		}
+
+		// Keep the top of the payload, but never turn a NaN into an infinity.
+		m := uint16(mant >> 13)
+		if m == 0 {
+			m = 0x200
+		}
+		return sign | 0x7C00 | m
+	}
+
+	e := exp - 127 + 15
+	if e >= 0x1F {
+		return sign | 0x7C00
+	}
+
+	if e <= 0 {
+		if e < -10 {
+			return sign
+		}
+
+		// Subnormal: shift the mantissa with its implicit bit in place.
+		mant |= 0x800000
+		shift := uint32(14 - e)
+		m := mant >> shift
+		rem := mant & (1<<shift - 1)
+		half := uint32(1) << (shift - 1)
+		if rem > half || (rem == half && m&1 == 1) {
+			m++
+		}
+		return sign | uint16(m)
+	}
+
+	h := uint32(e)<<10 | mant>>13
+	rem := mant & 0x1FFF
+	if rem > 0x1000 || (rem == 0x1000 && h&1 == 1) {
+		// A carry into the exponent is the correct rounding, up to infinity.
+		h++
+	}
+	return sign | uint16(h)
+}
+
+// Float16frombits returns the float32 value of the IEEE 754
+// half-precision representation h. The conversion is exact.
+func Float16frombits(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1F
+	mant := uint32(h & 0x3FF)
+
+	switch exp {
+	case 0x1F:
+		return math.Float32frombits(sign | 0x7F800000 | mant<<13)
+	case 0:
+		f := float32(math.Ldexp(float64(mant), -24))
+		if sign != 0 {
+			f = -f
+		}
+		return f
+	}
+
+	return math.Float32frombits(sign | (exp-15+127)<<23 | mant<<13)
+}
+
+// BFloat16bits returns the bfloat16 representation of f, the upper half
+// of its float32 bits rounded to nearest even. NaNs stay NaNs.
+func BFloat16bits(f float32) uint16 {
+	b := math.Float32bits(f)
+	if b&0x7FFFFFFF > 0x7F800000 {
+		return uint16(b>>16) | 0x40
+	}
+
+	return uint16((b + 0x7FFF + (b>>16)&1) >> 16)
+}
+
+// BFloat16frombits returns the float32 value of the bfloat16
+// representation h. The conversion is exact.
+func BFloat16frombits(h uint16) float32 {
+	return math.Float32frombits(uint32(h) << 16)
+}
+
+func readFloat16(r Reader, format string) (float32, error) {
+	if fr, ok := r.(Float16Reader); ok {
+		if format == tagTypeBFloat16 {
+			return fr.ReadBFloat16()
+		}
+		return fr.ReadFloat16()
+	}
+
+	h, err := r.ReadUint16()
+	if err != nil {
+		return 0, err
+	}
+	if format == tagTypeBFloat16 {
+		return BFloat16frombits(h), nil
+	}
+	return Float16frombits(h), nil
+}
+
+func decodeFloat16(r Reader, fieldValue reflect.Value, format string) error {
+	switch fieldValue.Kind() {
+	case reflect.Float32, reflect.Float64:
+	default:
+		return errors.New(format + " tag needs a float32 or float64 field")
+	}
+
+	f, err := readFloat16(r, format)
+	if err != nil {
+		return err
+	}
+
+	if fieldValue.CanSet() {
+		fieldValue.SetFloat(float64(f))
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package gocodec
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Float16RoundTrip(t *testing.T) {
+	for i := 0; i <= math.MaxUint16; i++ {
+		h := uint16(i)
+		f := Float16frombits(h)
+
+		if h&0x7C00 == 0x7C00 && h&0x3FF != 0 {
+			require.True(t, math.IsNaN(float64(f)), "0x%04x", h)
+			require.Equal(t, h&0xFE00, Float16bits(f)&0xFE00, "0x%04x", h)
+			continue
+		}
+
+		require.Equal(t, h, Float16bits(f), "0x%04x", h)
+	}
+}
+
+func Test_Float16Conversion(t *testing.T) {
+	tests := []struct {
+		f float32
+		h uint16
+	}{
+		{0, 0x0000},
+		{float32(math.Copysign(0, -1)), 0x8000},
+		{1, 0x3C00},
+		{-2, 0xC000},
+		{65504, 0x7BFF}, // max finite
+		{65519, 0x7BFF}, // rounds down to max finite
+		{65520, 0x7C00}, // rounds up to +Inf
+		{1e10, 0x7C00},  // overflow
+		{float32(math.Inf(-1)), 0xFC00},
+		{float32(math.Ldexp(1, -14)), 0x0400},   // min normal
+		{float32(math.Ldexp(1, -24)), 0x0001},   // min subnormal
+		{float32(math.Ldexp(1, -25)), 0x0000},   // tie rounds to even zero
+		{float32(math.Ldexp(1.5, -25)), 0x0001}, // above tie rounds up
+		{float32(math.Ldexp(1023, -24)), 0x03FF},
+		{1 + float32(math.Ldexp(1, -11)), 0x3C00}, // tie rounds to even
+		{1 + float32(math.Ldexp(3, -11)), 0x3C02}, // tie rounds to even
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.h, Float16bits(tt.f), "%v", tt.f)
+	}
+
+	require.True(t, math.IsNaN(float64(Float16frombits(Float16bits(float32(math.NaN()))))))
+}
+
+func Test_BFloat16Conversion(t *testing.T) {
+	require.Equal(t, uint16(0x3F80), BFloat16bits(1))
+	require.Equal(t, uint16(0x4049), BFloat16bits(math.Pi))
+	require.Equal(t, uint16(0xFF80), BFloat16bits(float32(math.Inf(-1))))
+	require.Equal(t, uint16(0x7F80), BFloat16bits(math.MaxFloat32))
+	require.Equal(t, uint16(0x0001), BFloat16bits(math.Float32frombits(0x00010000)))
+	require.True(t, math.IsNaN(float64(BFloat16frombits(BFloat16bits(float32(math.NaN()))))))
+	require.True(t, math.IsNaN(float64(BFloat16frombits(BFloat16bits(math.Float32frombits(0x7F800001))))))
+
+	for i := 0; i <= math.MaxUint16; i++ {
+		h := uint16(i)
+		if h&0x7F80 == 0x7F80 && h&0x7F != 0 {
+			continue
+		}
+		require.Equal(t, h, BFloat16bits(BFloat16frombits(h)), "0x%04x", h)
+	}
+}
+
+func Test_Float16Tags(t *testing.T) {
+	data := []byte{
+		0x3C, 0x00, // f16 1
+		0x00, 0x01, // f16 min subnormal
+		0x7C, 0x00, // f16 +Inf
+		0x3F, 0x80, // bf16 1
+		0x00, 0xC0, 0x00, 0x3C, // [f16] le -2, 1
+	}
+
+	var v struct {
+		One       float32    `bin:"f16"`
+		Subnormal float64    `bin:"f16"`
+		Inf       float32    `bin:"f16"`
+		BOne      float32    `bin:"bf16"`
+		LE        [2]float32 `bin:"[le,f16]"`
+	}
+
+	err := UnmarshalBE(data, &v)
+	require.NoError(t, err)
+	require.Equal(t, float32(1), v.One)
+	require.Equal(t, math.Ldexp(1, -24), v.Subnormal)
+	require.True(t, math.IsInf(float64(v.Inf), 1))
+	require.Equal(t, float32(1), v.BOne)
+	require.Equal(t, [2]float32{-2, 1}, v.LE)
+
+	var bad struct {
+		V uint16 `bin:"f16"`
+	}
+	err = UnmarshalBE(data, &bad)
+	require.EqualError(t, err, `failed set value to field "V": f16 tag needs a float32 or float64 field`)
+}
+
+func Test_Float16Buffer(t *testing.T) {
+	var buf Buffer
+	_, err := buf.WriteFloat16BE(1.5)
+	require.NoError(t, err)
+	_, err = buf.WriteFloat16LE(-0.5)
+	require.NoError(t, err)
+	_, err = buf.WriteBFloat16BE(2)
+	require.NoError(t, err)
+	_, err = buf.WriteBFloat16LE(-4)
+	require.NoError(t, err)
+
+	f, err := buf.ReadFloat16BE()
+	require.NoError(t, err)
+	require.Equal(t, float32(1.5), f)
+	f, err = buf.ReadFloat16LE()
+	require.NoError(t, err)
+	require.Equal(t, float32(-0.5), f)
+	f, err = buf.ReadBFloat16BE()
+	require.NoError(t, err)
+	require.Equal(t, float32(2), f)
+	f, err = buf.ReadBFloat16LE()
+	require.NoError(t, err)
+	require.Equal(t, float32(-4), f)
+}
+
+// readerOnly hides the optional interfaces of the wrapped Reader.
+type readerOnly struct {
+	Reader
+}
+
+func Test_Float16ReaderFallback(t *testing.T) {
+	var _ Float16Reader = NewReaderFromBytes(nil, binary.BigEndian, false).(Float16Reader)
+
+	r := readerOnly{NewReaderFromBytes([]byte{0x3C, 0x00, 0x3F, 0x80}, binary.BigEndian, false)}
+	_, ok := Reader(r).(Float16Reader)
+	require.False(t, ok)
+
+	f, err := readFloat16(r, tagTypeFloat16)
+	require.NoError(t, err)
+	require.Equal(t, float32(1), f)
+
+	f, err = readFloat16(r, tagTypeBFloat16)
+	require.NoError(t, err)
+	require.Equal(t, float32(1), f)
+}
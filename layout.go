@@ -0,0 +1,82 @@
+package gocodec
+
+import (
+	"reflect"
+)
+
+// FieldLayout describes the byte range a decoded value was read from.
+// The root node describes the whole value passed to Unmarshal, its
+// children describe struct fields and array elements in decode order.
+type FieldLayout struct {
+	Path   string
+	Offset int64
+	Length int64
+	Value  interface{}
+
+	Children []*FieldLayout
+}
+
+// WithLayout records the layout of every decoded field into root.
+// The previous content of root is replaced on each decode.
+func WithLayout(root *FieldLayout) Option {
+	return func(o *options) {
+		o.layout = root
+	}
+}
+
+// Find returns the deepest node whose byte range contains offset,
+// or nil if no node owns it.
+func (l *FieldLayout) Find(offset int64) *FieldLayout {
+	if l == nil || offset < l.Offset || offset >= l.Offset+l.Length {
+		return nil
+	}
+
+	for _, child := range l.Children {
+		if found := child.Find(offset); found != nil {
+			return found
+		}
+	}
+
+	return l
+}
+
+// Walk calls fn for l and all its descendants in depth-first order.
+func (l *FieldLayout) Walk(fn func(node *FieldLayout)) {
+	if l == nil {
+		return
+	}
+
+	fn(l)
+	for _, child := range l.Children {
+		child.Walk(fn)
+	}
+}
+
+// enterLayout appends a node for the value at path to the current layout
+// node and makes it current. It returns the new node and the previous
+// current node, or nils if the layout is not recorded.
+func (u *unmarshal) enterLayout(path string, start int64) (node, parent *FieldLayout) {
+	parent = u.state.node
+	if parent == nil {
+		return nil, nil
+	}
+
+	node = &FieldLayout{Path: path, Offset: start}
+	parent.Children = append(parent.Children, node)
+	u.state.node = node
+
+	return node, parent
+}
+
+func (u *unmarshal) leaveLayout(node, parent *FieldLayout, r Reader, value reflect.Value) {
+	if node == nil {
+		return
+	}
+
+	node.Length = offset(r) - node.Offset
+	if value.IsValid() && value.CanInterface() {
+		node.Value = value.Interface()
+	}
+
+	u.state.node = parent
+}
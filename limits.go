@@ -0,0 +1,143 @@
+package gocodec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// ErrLengthOverflow is returned for lengths too large to allocate.
+var ErrLengthOverflow = errors.New("binstruct: length overflows the allocation size")
+
+// Limits bounds the resources a decode may use, to protect against
+// corrupted or hostile length fields. A zero field means no limit.
+type Limits struct {
+	// MaxSliceLen is the maximum number of elements of a slice.
+	MaxSliceLen int64
+	// MaxStringLen is the maximum length in bytes of a string.
+	MaxStringLen int64
+	// MaxAlloc is the maximum number of bytes allocated for slices
+	// and strings during one decode.
+	MaxAlloc int64
+	// MaxDepth is the maximum nesting of structs, slices and arrays.
+	MaxDepth int
+}
+
+// WithLimits sets the resource limits of a decode.
+func WithLimits(l Limits) Option {
+	return func(o *options) {
+		o.limits = l
+	}
+}
+
+// A LimitError is returned when a decode exceeds one of its Limits.
+type LimitError struct {
+	Limit string // name of the Limits field
+	Value int64
+	Max   int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("binstruct: %s exceeded: %d > %d", e.Limit, e.Value, e.Max)
+}
+
+func checkLimit(limit string, value, max int64) error {
+	if max > 0 && value > max {
+		return &LimitError{Limit: limit, Value: value, Max: max}
+	}
+
+	return nil
+}
+
+// enter accounts one more level of nesting, it must be paired with leave.
+// It undoes the accounting itself when the limit is exceeded.
+func (u *unmarshal) enter() error {
+	u.state.nesting++
+	if err := checkLimit("MaxDepth", int64(u.state.nesting), int64(u.opts.limits.MaxDepth)); err != nil {
+		u.state.nesting--
+		return err
+	}
+
+	return nil
+}
+
+func (u *unmarshal) leave() {
+	u.state.nesting--
+}
+
+// allocate accounts n elements of size bytes before they are allocated.
+// When the number of remaining input bytes is known, it also fails with
+// io.ErrUnexpectedEOF if the elements can't be there, where minSize is
+// the encoded size of one element or 0 if it is unknown.
+func (u *unmarshal) allocate(r Reader, n, size, minSize int64) error {
+	if n < 0 {
+		return ErrNegativeCount
+	}
+	if n > math.MaxInt {
+		return ErrLengthOverflow
+	}
+
+	if minSize > 0 {
+		if rest, ok := remaining(r); ok && n > rest/minSize {
+			// Same errors as io.ReadFull would return.
+			if rest == 0 {
+				return io.EOF
+			}
+			return io.ErrUnexpectedEOF
+		}
+	}
+
+	if size > 0 && n > (math.MaxInt64-u.state.allocated)/size {
+		if u.opts.limits.MaxAlloc == 0 {
+			return ErrLengthOverflow
+		}
+		u.state.allocated = math.MaxInt64 // saturate instead of wrapping around
+	} else {
+		u.state.allocated += n * size
+	}
+
+	return checkLimit("MaxAlloc", u.state.allocated, u.opts.limits.MaxAlloc)
+}
+
+// remaining returns the number of unread input bytes, if r knows it.
+func remaining(r Reader) (int64, bool) {
+	var src interface{} = r
+	if rr, ok := r.(*reader); ok {
+		src = rr.r
+	}
+
+	switch l := src.(type) {
+	case interface{ Len() int }:
+		return int64(l.Len()), true
+	case interface{ Len() int64 }:
+		return l.Len(), true
+	}
+
+	return 0, false
+}
+
+// fixedSize returns the encoded size of a value of type t decoded with
+// data, or 0 if it is not known before decoding.
+func fixedSize(t reflect.Type, data *fieldReadData) int64 {
+	if data != nil && !data.plain() {
+		return 0
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if data != nil && data.Length != nil {
+			return *data.Length
+		}
+		if t.Kind() == reflect.Int || t.Kind() == reflect.Uint {
+			return 0
+		}
+		return int64(t.Size())
+	case reflect.Float32, reflect.Float64, reflect.Bool:
+		return int64(t.Size())
+	}
+
+	return 0
+}
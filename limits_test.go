@@ -0,0 +1,161 @@
+package gocodec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LimitsSliceLen(t *testing.T) {
+	var v struct {
+		Count uint32
+		Items []uint16 `bin:"len:Count"`
+	}
+
+	data := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x01}
+	err := UnmarshalBE(data, &v, WithLimits(Limits{MaxSliceLen: 1024}))
+
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, &LimitError{Limit: "MaxSliceLen", Value: 0xFFFFFFFF, Max: 1024}, limitErr)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "Items", decodeErr.Path)
+	require.Nil(t, v.Items)
+}
+
+func Test_LimitsRemainingInput(t *testing.T) {
+	var v struct {
+		Count uint32
+		Data  []byte   `bin:"len:Count"`
+		Items []uint16 `bin:"len:2"`
+	}
+
+	// Without limits, the hostile length is rejected before allocating
+	// because the input is known to be shorter.
+	err := UnmarshalBE([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x01}, &v)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	err = UnmarshalBE([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x01}, &v)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "Items", decodeErr.Path)
+}
+
+func Test_LimitsStringLenAndAlloc(t *testing.T) {
+	var v struct {
+		A string `bin:"len:4"`
+		B []byte `bin:"len:4"`
+	}
+
+	data := []byte("abcdefgh")
+
+	err := UnmarshalBE(data, &v, WithLimits(Limits{MaxStringLen: 3}))
+	require.Equal(t, "MaxStringLen", limitName(err))
+
+	err = UnmarshalBE(data, &v, WithLimits(Limits{MaxAlloc: 6}))
+	require.Equal(t, "MaxAlloc", limitName(err))
+
+	// The allocation count starts over with every decode.
+	dec := NewDecoder(bytes.NewReader(append(data, data...)), binary.BigEndian, WithLimits(Limits{MaxAlloc: 8}))
+	require.NoError(t, dec.Decode(&v))
+	require.NoError(t, dec.Decode(&v))
+}
+
+func Test_LimitsDepth(t *testing.T) {
+	type inner struct {
+		A uint8
+	}
+
+	var v struct {
+		Nested struct {
+			Items [2]inner
+		}
+	}
+
+	// Nested, Items and the inner structs are three levels.
+	data := []byte{0x01, 0x02}
+	require.NoError(t, UnmarshalBE(data, &v, WithLimits(Limits{MaxDepth: 3})))
+
+	err := UnmarshalBE(data, &v, WithLimits(Limits{MaxDepth: 2}))
+	require.Equal(t, "MaxDepth", limitName(err))
+
+	var flat struct {
+		A uint8
+	}
+	require.NoError(t, UnmarshalBE(data, &flat, WithLimits(Limits{MaxDepth: 1})))
+
+	var one struct {
+		Nested inner
+	}
+	require.NoError(t, UnmarshalBE(data, &one, WithLimits(Limits{MaxDepth: 1})))
+}
+
+func Test_LimitsAllocOverflow(t *testing.T) {
+	limits := WithLimits(Limits{MaxAlloc: 1 << 20})
+
+	// Struct elements have no known encoded size, so the remaining input
+	// can't reject the length.
+	var v struct {
+		Count uint64
+		Items []struct{ A, B uint64 } `bin:"len:Count"`
+	}
+	data := []byte{0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	err := UnmarshalBE(data, &v, limits)
+	require.Equal(t, "MaxAlloc", limitName(err))
+
+	// A stream doesn't know its remaining input at all.
+	var w struct {
+		Count uint64
+		Items []uint64 `bin:"len:Count"`
+	}
+	data = []byte{0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	err = NewStreamDecoder(bytes.NewBuffer(data), binary.BigEndian, limits).Decode(&w)
+	require.Equal(t, "MaxAlloc", limitName(err))
+
+	err = NewStreamDecoder(bytes.NewBuffer(data), binary.BigEndian).Decode(&w)
+	require.ErrorIs(t, err, ErrLengthOverflow)
+}
+
+func Test_LimitsDepthReusedReader(t *testing.T) {
+	var deep struct {
+		Nested struct {
+			A uint8
+		}
+	}
+	var flat struct {
+		A uint8
+	}
+
+	r := NewReaderFromBytes([]byte{0x01, 0x02, 0x03}, binary.BigEndian, false, WithLimits(Limits{MaxDepth: 1}))
+	require.NoError(t, r.Unmarshal(&deep))
+
+	var deeper struct {
+		Nested struct {
+			Inner struct {
+				A uint8
+			}
+		}
+	}
+	require.Equal(t, "MaxDepth", limitName(r.Unmarshal(&deeper)))
+
+	// A failed decode leaves no nesting behind.
+	require.NoError(t, r.Unmarshal(&flat))
+	require.NoError(t, r.Unmarshal(&deep))
+}
+
+func limitName(err error) string {
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		return ""
+	}
+
+	return limitErr.Limit
+}
@@ -0,0 +1,29 @@
+package gocodec
+
+import (
+	"context"
+)
+
+// An Option configures how Unmarshal, Decoder and Reader decode values.
+type Option func(*options)
+
+type options struct {
+	layout *FieldLayout
+	tracer Tracer
+	ctx    context.Context
+	limits Limits
+
+	zeroCopy        bool
+	zeroCopyStrings bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	return o
+}
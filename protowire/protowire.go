@@ -0,0 +1,97 @@
+// Package protowire reads and writes the protocol buffers wire format
+// without generated code, on top of gocodec readers and cursors.
+//
+// A message is a sequence of fields, each a tag followed by a value whose
+// encoding depends on the wire type:
+//
+//	for {
+//		num, typ, err := r.ReadTag()
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//	}
+package protowire
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Number is a protobuf field number.
+type Number int32
+
+const (
+	MinValidNumber Number = 1
+	MaxValidNumber Number = 1<<29 - 1
+)
+
+// Type is a protobuf wire type.
+type Type int8
+
+const (
+	VarintType     Type = 0
+	Fixed64Type    Type = 1
+	BytesType      Type = 2
+	StartGroupType Type = 3
+	EndGroupType   Type = 4
+	Fixed32Type    Type = 5
+)
+
+func (t Type) String() string {
+	switch t {
+	case VarintType:
+		return "varint"
+	case Fixed64Type:
+		return "fixed64"
+	case BytesType:
+		return "bytes"
+	case StartGroupType:
+		return "start group"
+	case EndGroupType:
+		return "end group"
+	case Fixed32Type:
+		return "fixed32"
+	}
+	return fmt.Sprintf("Type(%d)", int8(t))
+}
+
+// MaxGroupDepth is the maximum nesting of groups SkipField accepts.
+const MaxGroupDepth = 10000
+
+var (
+	// ErrInvalidTag is returned for tags with a field number out of range.
+	ErrInvalidTag = errors.New("protowire: invalid field number")
+	// ErrInvalidWireType is returned for the reserved wire types 6 and 7
+	// and for unmatched end groups.
+	ErrInvalidWireType = errors.New("protowire: invalid wire type")
+	// ErrOverflow is returned for varints longer than 10 bytes or
+	// exceeding 64 bits, and lengths exceeding the int range.
+	ErrOverflow = errors.New("protowire: value overflows")
+	// ErrGroupDepth is returned when groups nest deeper than MaxGroupDepth.
+	ErrGroupDepth = errors.New("protowire: groups nested too deep")
+)
+
+// EncodeTag combines a field number and a wire type into a tag.
+func EncodeTag(num Number, typ Type) uint64 {
+	return uint64(num)<<3 | uint64(typ&7)
+}
+
+// DecodeTag splits a tag into its field number and wire type.
+func DecodeTag(x uint64) (Number, Type) {
+	if x>>3 > math.MaxInt32 {
+		return -1, 0
+	}
+	return Number(x >> 3), Type(x & 7)
+}
+
+// EncodeZigZag encodes a signed value for the sint32 and sint64 types.
+func EncodeZigZag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// DecodeZigZag decodes a sint32 or sint64 value.
+func DecodeZigZag(x uint64) int64 {
+	return int64(x>>1) ^ -int64(x&1)
+}
@@ -0,0 +1,181 @@
+package protowire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/meta-quick/gocodec"
+	"github.com/stretchr/testify/require"
+)
+
+var message = []byte{
+	0x08, 0x96, 0x01, // 1: varint 150
+	0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g', // 2: "testing"
+	0x1D, 0x01, 0x02, 0x03, 0x04, // 3: fixed32 0x04030201
+	0x21, 0x01, 0, 0, 0, 0, 0, 0, 0x80, // 4: fixed64
+	0x2B, 0x30, 0x01, 0x2C, // 5: group { 6: varint 1 }
+	0x38, 0x03, // 7: sint -2
+}
+
+type field struct {
+	num Number
+	typ Type
+	val interface{}
+}
+
+func readAll(t *testing.T, r *Reader) []field {
+	var fields []field
+	for {
+		num, typ, err := r.ReadTag()
+		if err == io.EOF {
+			return fields
+		}
+		require.NoError(t, err)
+
+		f := field{num: num, typ: typ}
+		switch typ {
+		case VarintType:
+			if num == 7 {
+				f.val, err = r.ReadSvarint()
+			} else {
+				f.val, err = r.ReadVarint()
+			}
+		case BytesType:
+			f.val, err = r.ReadLengthDelimited()
+		case Fixed32Type:
+			f.val, err = r.ReadFixed32()
+		case Fixed64Type:
+			f.val, err = r.ReadFixed64()
+		default:
+			err = r.SkipField(num, typ)
+		}
+		require.NoError(t, err)
+		fields = append(fields, f)
+	}
+}
+
+func Test_Reader(t *testing.T) {
+	want := []field{
+		{1, VarintType, uint64(150)},
+		{2, BytesType, []byte("testing")},
+		{3, Fixed32Type, uint32(0x04030201)},
+		{4, Fixed64Type, uint64(0x8000000000000001)},
+		{5, StartGroupType, nil},
+		{7, VarintType, int64(-2)},
+	}
+
+	c := gocodec.NewCursor(message)
+	require.Equal(t, want, readAll(t, NewCursorReader(&c)))
+
+	r := gocodec.NewReaderFromBytes(message, binary.LittleEndian, false)
+	require.Equal(t, want, readAll(t, NewReader(r)))
+
+	sr := gocodec.NewStreamReader(bytes.NewReader(message), binary.LittleEndian)
+	require.Equal(t, want, readAll(t, NewReader(sr)))
+}
+
+func Test_SkipField(t *testing.T) {
+	c := gocodec.NewCursor(message)
+	r := NewCursorReader(&c)
+	for {
+		num, typ, err := r.ReadTag()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.NoError(t, r.SkipField(num, typ))
+	}
+
+	for i := 1; i < len(message); i++ {
+		c := gocodec.NewCursor(message[:i])
+		r := NewCursorReader(&c)
+		var err error
+		for err == nil {
+			var num Number
+			var typ Type
+			num, typ, err = r.ReadTag()
+			if err == nil {
+				err = r.SkipField(num, typ)
+			}
+		}
+		if i == 3 || i == 12 || i == 17 || i == 26 || i == 30 {
+			require.ErrorIs(t, err, io.EOF, "prefix %d", i)
+		} else {
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF, "prefix %d", i)
+		}
+	}
+}
+
+func Test_ReaderErrors(t *testing.T) {
+	cases := []struct {
+		data []byte
+		err  error
+	}{
+		{[]byte{0x00}, ErrInvalidTag},
+		{[]byte{0x0E}, ErrInvalidWireType},
+		{[]byte{0x0C}, ErrInvalidWireType},
+		{[]byte{0x0B, 0x14}, ErrInvalidWireType},
+		{[]byte{0x08, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x02}, ErrOverflow},
+		{[]byte{0x0A, 0xFF, 0xFF, 0xFF, 0xFF, 0x0F}, ErrOverflow},
+		{[]byte{0x0A, 0x05, 0x01}, io.ErrUnexpectedEOF},
+		{bytes.Repeat([]byte{0x0B}, MaxGroupDepth+2), ErrGroupDepth},
+	}
+
+	for _, tc := range cases {
+		c := gocodec.NewCursor(tc.data)
+		r := NewCursorReader(&c)
+		num, typ, err := r.ReadTag()
+		if err == nil {
+			err = r.SkipField(num, typ)
+		}
+		require.ErrorIs(t, err, tc.err, "% x", tc.data)
+	}
+}
+
+func Test_WriterRoundTrip(t *testing.T) {
+	var buf gocodec.Buffer
+	w := NewWriter(&buf)
+
+	c := gocodec.NewCursor(message)
+	for _, f := range readAll(t, NewCursorReader(&c)) {
+		if f.typ == StartGroupType {
+			require.NoError(t, w.WriteTag(f.num, f.typ))
+			require.NoError(t, w.WriteTag(6, VarintType))
+			require.NoError(t, w.WriteVarint(1))
+			require.NoError(t, w.WriteTag(f.num, EndGroupType))
+			continue
+		}
+
+		require.NoError(t, w.WriteTag(f.num, f.typ))
+		switch v := f.val.(type) {
+		case uint64:
+			if f.typ == Fixed64Type {
+				require.NoError(t, w.WriteFixed64(v))
+			} else {
+				require.NoError(t, w.WriteVarint(v))
+			}
+		case int64:
+			require.NoError(t, w.WriteSvarint(v))
+		case uint32:
+			require.NoError(t, w.WriteFixed32(v))
+		case []byte:
+			require.NoError(t, w.WriteLengthDelimited(v))
+		}
+	}
+
+	out := make([]byte, len(message))
+	_, err := buf.Read(out)
+	require.NoError(t, err)
+	require.Equal(t, message, out)
+	require.Equal(t, int64(0), buf.Len())
+}
+
+func Test_ZigZag(t *testing.T) {
+	for _, v := range []int64{0, -1, 1, -2, math.MaxInt64, math.MinInt64} {
+		require.Equal(t, v, DecodeZigZag(EncodeZigZag(v)))
+	}
+	require.Equal(t, uint64(3), EncodeZigZag(-2))
+}
@@ -0,0 +1,249 @@
+package protowire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/meta-quick/gocodec"
+)
+
+// maxChunk bounds single allocations for length-delimited values read from
+// a gocodec.Reader, so a corrupted length fails on the missing data
+// instead of allocating it up front.
+const maxChunk = 64 << 10
+
+type source interface {
+	ReadByte() (byte, error)
+	// readN reads exactly n bytes, or returns io.ErrUnexpectedEOF.
+	readN(n int) ([]byte, error)
+	skip(n int) error
+}
+
+// Reader reads wire format values.
+type Reader struct {
+	src source
+}
+
+// NewReader returns a Reader over r.
+func NewReader(r gocodec.Reader) *Reader {
+	return &Reader{src: readerSource{r}}
+}
+
+// NewCursorReader returns a Reader over c. Byte values returned by
+// ReadLengthDelimited alias the cursor's buffer.
+func NewCursorReader(c *gocodec.Cursor[byte]) *Reader {
+	return &Reader{src: cursorSource{c}}
+}
+
+// ReadTag reads a field tag. It returns io.EOF if the input ends before the
+// tag, which is the normal end of a message.
+func (r *Reader) ReadTag() (Number, Type, error) {
+	x, err := r.ReadVarint()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	num, typ := DecodeTag(x)
+	if num < MinValidNumber || num > MaxValidNumber {
+		return 0, 0, fmt.Errorf("%w: %d", ErrInvalidTag, x>>3)
+	}
+	if typ > Fixed32Type {
+		return 0, 0, fmt.Errorf("%w: %d", ErrInvalidWireType, typ)
+	}
+
+	return num, typ, nil
+}
+
+// ReadVarint reads a base 128 varint.
+func (r *Reader) ReadVarint() (uint64, error) {
+	var x uint64
+	for i := 0; i < binary.MaxVarintLen64; i++ {
+		b, err := r.src.ReadByte()
+		if err != nil {
+			if i > 0 && err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+
+		if i == binary.MaxVarintLen64-1 && b > 1 {
+			return 0, ErrOverflow
+		}
+
+		x |= uint64(b&0x7F) << (7 * i)
+		if b < 0x80 {
+			return x, nil
+		}
+	}
+
+	return 0, ErrOverflow
+}
+
+// ReadSvarint reads a zigzag encoded varint (sint32, sint64).
+func (r *Reader) ReadSvarint() (int64, error) {
+	x, err := r.ReadVarint()
+	return DecodeZigZag(x), err
+}
+
+// ReadFixed32 reads a little endian 32 bit value (fixed32, sfixed32, float).
+func (r *Reader) ReadFixed32() (uint32, error) {
+	b, err := r.src.readN(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// ReadFixed64 reads a little endian 64 bit value (fixed64, sfixed64, double).
+func (r *Reader) ReadFixed64() (uint64, error) {
+	b, err := r.src.readN(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+// ReadLengthDelimited reads a length prefixed value (bytes, string,
+// embedded messages and packed repeated fields).
+func (r *Reader) ReadLengthDelimited() ([]byte, error) {
+	n, err := r.readLength()
+	if err != nil {
+		return nil, err
+	}
+	return r.src.readN(n)
+}
+
+func (r *Reader) readLength() (int, error) {
+	x, err := r.ReadVarint()
+	if err != nil {
+		return 0, unexpected(err)
+	}
+	if x > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: length %d", ErrOverflow, x)
+	}
+	return int(x), nil
+}
+
+// SkipField skips the value of a field whose tag was just read. For a start
+// group, everything up to the matching end group is skipped.
+func (r *Reader) SkipField(num Number, typ Type) error {
+	return r.skipField(num, typ, 0)
+}
+
+func (r *Reader) skipField(num Number, typ Type, depth int) error {
+	var err error
+	switch typ {
+	case VarintType:
+		_, err = r.ReadVarint()
+	case Fixed32Type:
+		err = r.src.skip(4)
+	case Fixed64Type:
+		err = r.src.skip(8)
+	case BytesType:
+		var n int
+		n, err = r.readLength()
+		if err == nil {
+			err = r.src.skip(n)
+		}
+	case StartGroupType:
+		if depth >= MaxGroupDepth {
+			return ErrGroupDepth
+		}
+		for {
+			var n Number
+			var t Type
+			n, t, err = r.ReadTag()
+			if err != nil {
+				break
+			}
+			if t == EndGroupType {
+				if n != num {
+					return fmt.Errorf("%w: end group %d in group %d", ErrInvalidWireType, n, num)
+				}
+				return nil
+			}
+			if err = r.skipField(n, t, depth+1); err != nil {
+				break
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidWireType, typ)
+	}
+
+	return unexpected(err)
+}
+
+// unexpected maps io.EOF inside a field to io.ErrUnexpectedEOF.
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+type readerSource struct {
+	r gocodec.Reader
+}
+
+func (s readerSource) ReadByte() (byte, error) {
+	return s.r.ReadByte()
+}
+
+func (s readerSource) readN(n int) ([]byte, error) {
+	if n <= maxChunk {
+		_, b, err := s.r.ReadBytes(n)
+		return b, unexpected(err)
+	}
+
+	b := make([]byte, 0, maxChunk)
+	for len(b) < n {
+		_, chunk, err := s.r.ReadBytes(min(n-len(b), maxChunk))
+		if err != nil {
+			return nil, unexpected(err)
+		}
+		b = append(b, chunk...)
+	}
+	return b, nil
+}
+
+func (s readerSource) skip(n int) error {
+	for n > 0 {
+		an, _, err := s.r.ReadBytes(min(n, maxChunk))
+		if err != nil {
+			return unexpected(err)
+		}
+		n -= an
+	}
+	return nil
+}
+
+type cursorSource struct {
+	c *gocodec.Cursor[byte]
+}
+
+func (s cursorSource) ReadByte() (byte, error) {
+	if s.c.EOF() {
+		return 0, io.EOF
+	}
+	return s.c.Read()
+}
+
+func (s cursorSource) readN(n int) ([]byte, error) {
+	if int64(n) > s.c.Len() {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if n == 0 {
+		return []byte{}, nil
+	}
+	return s.c.ReadN(n)
+}
+
+func (s cursorSource) skip(n int) error {
+	if int64(n) > s.c.Len() {
+		return io.ErrUnexpectedEOF
+	}
+	_, err := s.c.Advance(n)
+	return err
+}
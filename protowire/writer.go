@@ -0,0 +1,61 @@
+package protowire
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// Writer writes wire format values to an io.Writer such as *gocodec.Buffer.
+type Writer struct {
+	w       io.Writer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+// NewWriter returns a Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// WriteTag writes a field tag.
+func (w *Writer) WriteTag(num Number, typ Type) error {
+	return w.WriteVarint(EncodeTag(num, typ))
+}
+
+// WriteVarint writes a base 128 varint.
+func (w *Writer) WriteVarint(v uint64) error {
+	n := binary.PutUvarint(w.scratch[:], v)
+	return w.write(w.scratch[:n])
+}
+
+// WriteSvarint writes a zigzag encoded varint (sint32, sint64).
+func (w *Writer) WriteSvarint(v int64) error {
+	return w.WriteVarint(EncodeZigZag(v))
+}
+
+// WriteFixed32 writes a little endian 32 bit value.
+func (w *Writer) WriteFixed32(v uint32) error {
+	binary.LittleEndian.PutUint32(w.scratch[:], v)
+	return w.write(w.scratch[:4])
+}
+
+// WriteFixed64 writes a little endian 64 bit value.
+func (w *Writer) WriteFixed64(v uint64) error {
+	binary.LittleEndian.PutUint64(w.scratch[:], v)
+	return w.write(w.scratch[:8])
+}
+
+// WriteLengthDelimited writes b prefixed with its length.
+func (w *Writer) WriteLengthDelimited(b []byte) error {
+	if err := w.WriteVarint(uint64(len(b))); err != nil {
+		return err
+	}
+	return w.write(b)
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.w.Write(b)
+	if err == nil && n < len(b) {
+		err = io.ErrShortWrite
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package gocodec
+
+import (
+	"bufio"
+	"io"
+)
+
+// UnescapeRBSP removes the H.264/H.265 emulation prevention bytes from a
+// NAL unit payload: every 0x03 following two zero bytes is dropped. The
+// result is a new slice, b is left unchanged.
+func UnescapeRBSP(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	zeros := 0
+	for _, c := range b {
+		if zeros >= 2 && c == 0x03 {
+			zeros = 0
+			continue
+		}
+
+		if c == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+		out = append(out, c)
+	}
+
+	return out
+}
+
+// NewRBSPReader returns a reader which removes emulation prevention bytes
+// from r like UnescapeRBSP. Use it with NewStreamReader to decode
+// parameter sets straight from a NAL unit stream.
+func NewRBSPReader(r io.Reader) io.Reader {
+	switch br := r.(type) {
+	case bufferedByteReader:
+		return &rbspReader{r: br, available: br.Buffered}
+	case lenByteReader:
+		return &rbspReader{r: br, available: br.Len}
+	}
+
+	br := bufio.NewReader(r)
+	return &rbspReader{r: br, available: br.Buffered}
+}
+
+type bufferedByteReader interface {
+	io.ByteReader
+	Buffered() int
+}
+
+type lenByteReader interface {
+	io.ByteReader
+	Len() int
+}
+
+type rbspReader struct {
+	r         io.ByteReader
+	available func() int // bytes r returns without blocking
+	zeros     int
+}
+
+// Read returns as soon as the bytes available without blocking are
+// consumed, so a live stream is not held until p is full.
+func (r *rbspReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if n > 0 && r.available() == 0 {
+			break
+		}
+
+		c, err := r.r.ReadByte()
+		if err != nil {
+			return n, err
+		}
+
+		if r.zeros >= 2 && c == 0x03 {
+			r.zeros = 0
+			continue
+		}
+
+		if c == 0 {
+			r.zeros++
+		} else {
+			r.zeros = 0
+		}
+		p[n] = c
+		n++
+	}
+
+	return n, nil
+}
@@ -3,12 +3,11 @@ package gocodec
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/hex"
 	"errors"
-	"fmt"
 	"io"
 	"io/ioutil"
 	"math"
+	"os"
 )
 
 var (
@@ -69,34 +68,86 @@ type Reader interface {
 	WithOrder(order binary.ByteOrder) Reader
 }
 
+// Float16Reader is implemented by Readers which read half precision
+// floats. The Readers of this package implement it. It is separate from
+// Reader so existing Reader implementations keep compiling; the f16 and
+// bf16 tags fall back to ReadUint16 for Readers without it.
+type Float16Reader interface {
+	// ReadFloat16 read two bytes of IEEE half precision and return float32 value
+	ReadFloat16() (float32, error)
+	// ReadBFloat16 read two bytes of bfloat16 and return float32 value
+	ReadBFloat16() (float32, error)
+}
+
+// BCDReader is implemented by Readers which read packed and telephony BCD.
+// The Readers of this package implement it. It is separate from Reader so
+// existing Reader implementations keep compiling; the bcd and tbcd tags
+// fall back to ReadBytes for Readers without it.
+type BCDReader interface {
+	// ReadBCD read n bytes of packed BCD and return the digits
+	ReadBCD(n int) (string, error)
+	// ReadTBCD read n bytes of telephony BCD and return the digits
+	ReadTBCD(n int) (string, error)
+}
+
 // NewReader returns a new reader that reads from r with byte order.
-// If debug set true, all read bytes and offsets will be displayed.
-func NewReader(r io.ReadSeeker, order binary.ByteOrder, debug bool) Reader {
+// If debug set true and no tracer is given in opts, all read bytes and
+// offsets will be displayed on stdout.
+// The options configure values decoded with Unmarshal.
+func NewReader(r io.ReadSeeker, order binary.ByteOrder, debug bool, opts ...Option) Reader {
+	o := newOptions(opts)
+	if debug && o.tracer == nil {
+		o.tracer = NewHexDumpTracer(os.Stdout)
+	}
+
 	return &reader{
 		r:     r,
 		order: order,
-		debug: debug,
+		opts:  o,
+		state: &decodeState{},
 	}
 }
 
 // NewReaderFromBytes returns a new reader that reads from data with byte order.
-// If debug set true, all read bytes and offsets will be displayed.
-func NewReaderFromBytes(data []byte, order binary.ByteOrder, debug bool) Reader {
-	return NewReader(bytes.NewReader(data), order, debug)
+// If debug set true and no tracer is given in opts, all read bytes and
+// offsets will be displayed on stdout.
+// The options configure values decoded with Unmarshal.
+func NewReaderFromBytes(data []byte, order binary.ByteOrder, debug bool, opts ...Option) Reader {
+	r := NewReader(bytes.NewReader(data), order, debug, opts...).(*reader)
+	r.data = data
+	return r
 }
 
 type reader struct {
 	r     io.ReadSeeker
 	order binary.ByteOrder
 
-	debug bool
+	opts  *options
+	state *decodeState
+
+	data []byte // input of NewReaderFromBytes, for zero-copy reads
+}
+
+// tell returns the current offset without notifying the tracer.
+func (r *reader) tell() int64 {
+	off, err := r.r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+
+	return off
 }
 
 func (r *reader) ReadAll() ([]byte, error) {
+	var start int64
+	if r.opts.tracer != nil {
+		start = r.tell()
+	}
+
 	b, err := ioutil.ReadAll(r)
 
-	if r.debug {
-		fmt.Printf("ReadAll(): %s", hex.Dump(b))
+	if r.opts.tracer != nil {
+		r.opts.tracer.OnRead(start, -1, b, err)
 	}
 
 	return b, err
@@ -112,11 +163,20 @@ func (r *reader) ReadBytes(n int) (an int, b []byte, err error) {
 		return 0, []byte{}, nil
 	}
 
+	if err = contextErr(r.opts.ctx); err != nil {
+		return 0, nil, err
+	}
+
+	var start int64
+	if r.opts.tracer != nil {
+		start = r.tell()
+	}
+
 	b = make([]byte, n)
 	an, err = io.ReadFull(r, b)
 
-	if r.debug {
-		fmt.Printf("Read(want: %d|actual: %d): %s", n, an, hex.Dump(b))
+	if r.opts.tracer != nil {
+		r.opts.tracer.OnRead(start, n, b[:an], err)
 	}
 
 	if err != nil {
@@ -234,6 +294,42 @@ func (r *reader) ReadIntX(x int) (int64, error) {
 	return i, nil
 }
 
+func (r *reader) ReadBCD(n int) (string, error) {
+	_, b, err := r.ReadBytes(n)
+	if err != nil {
+		return "", err
+	}
+
+	return DecodeBCD(b)
+}
+
+func (r *reader) ReadTBCD(n int) (string, error) {
+	_, b, err := r.ReadBytes(n)
+	if err != nil {
+		return "", err
+	}
+
+	return DecodeTBCD(b)
+}
+
+func (r *reader) ReadFloat16() (float32, error) {
+	b, err := r.ReadUint16()
+	if err != nil {
+		return 0, err
+	}
+
+	return Float16frombits(b), nil
+}
+
+func (r *reader) ReadBFloat16() (float32, error) {
+	b, err := r.ReadUint16()
+	if err != nil {
+		return 0, err
+	}
+
+	return BFloat16frombits(b), nil
+}
+
 func (r *reader) ReadFloat32() (float32, error) {
 	b, err := r.ReadUint32()
 	if err != nil {
@@ -263,24 +359,30 @@ func (r *reader) Read(p []byte) (n int, err error) {
 func (r *reader) Seek(offset int64, whence int) (int64, error) {
 	i, err := r.r.Seek(offset, whence)
 
-	if r.debug {
-		whenceStr := "invalid"
-		switch whence {
-		case io.SeekStart:
-			whenceStr = "SeekStart"
-		case io.SeekCurrent:
-			whenceStr = "SeekCurrent"
-		case io.SeekEnd:
-			whenceStr = "SeekEnd"
-		}
-
-		fmt.Printf("Seek(%d, %s) CurPos:%d\n", offset, whenceStr, i)
+	if r.opts.tracer != nil {
+		r.opts.tracer.OnSeek(offset, whence, i, err)
 	}
 
 	return i, err
 }
 
 func (r *reader) Peek(n int) ([]byte, error) {
+	if p, ok := r.r.(interface{ Peek(n int) ([]byte, error) }); ok {
+		b, err := p.Peek(n)
+
+		// The seekable path below reports its read to the tracer, so
+		// report the peeked bytes here as well.
+		if r.opts.tracer != nil {
+			r.opts.tracer.OnRead(r.tell(), n, b, err)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return append([]byte(nil), b...), nil
+	}
+
 	rn, b, err := r.ReadBytes(n)
 	if err != nil {
 		return nil, err
@@ -291,10 +393,20 @@ func (r *reader) Peek(n int) ([]byte, error) {
 }
 
 func (r *reader) Unmarshal(v interface{}) error {
-	u := &unmarshal{r}
+	u := &unmarshal{
+		r:     r,
+		opts:  r.opts,
+		state: r.state,
+	}
 	return u.Unmarshal(v)
 }
 
 func (r *reader) WithOrder(order binary.ByteOrder) Reader {
-	return NewReader(r, order, r.debug)
+	return &reader{
+		r:     r.r,
+		order: order,
+		opts:  r.opts,
+		state: r.state,
+		data:  r.data,
+	}
 }
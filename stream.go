@@ -0,0 +1,115 @@
+package gocodec
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrNotSeekable is returned when a stream reader is asked for a backward
+	// or end relative seek, which can't be done without an io.Seeker.
+	ErrNotSeekable = errors.New("binstruct: seek not supported on stream")
+)
+
+// NewStreamReader returns a new reader that reads from the non-seekable
+// stream r with byte order. Peek and forward seeks (offset and offsetStart
+// tags pointing forward) are served by internal buffering, backward and
+// end relative seeks fail with ErrNotSeekable.
+func NewStreamReader(r io.Reader, order binary.ByteOrder, opts ...Option) Reader {
+	return NewReader(newStreamReader(r), order, false, opts...)
+}
+
+// NewStreamDecoder returns a new decoder that reads from the non-seekable
+// stream r with byte order. See NewStreamReader for the seek limitations.
+func NewStreamDecoder(r io.Reader, order binary.ByteOrder, opts ...Option) *Decoder {
+	return NewDecoder(newStreamReader(r), order, opts...)
+}
+
+// streamReader adapts an io.Reader to io.ReadSeeker by tracking the
+// position and discarding bytes for forward seeks.
+type streamReader struct {
+	src     io.Reader
+	r       *bufio.Reader
+	pending []byte // peeked, not yet consumed bytes
+	pos     int64
+}
+
+func newStreamReader(r io.Reader) *streamReader {
+	return &streamReader{
+		src: r,
+		r:   bufio.NewReader(r),
+	}
+}
+
+func (s *streamReader) Read(p []byte) (int, error) {
+	if len(s.pending) > 0 {
+		n := copy(p, s.pending)
+		s.pending = s.pending[n:]
+		s.pos += int64(n)
+		return n, nil
+	}
+
+	n, err := s.r.Read(p)
+	s.pos += int64(n)
+	return n, err
+}
+
+// Peek returns the next n bytes without consuming them. The returned
+// slice is only valid until the next read.
+func (s *streamReader) Peek(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, ErrNegativeCount
+	}
+
+	if missing := n - len(s.pending); missing > 0 {
+		more := make([]byte, missing)
+		m, err := io.ReadFull(s.r, more)
+		s.pending = append(s.pending, more[:m]...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.pending[:n], nil
+}
+
+func (s *streamReader) Seek(offset int64, whence int) (int64, error) {
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = offset
+	case io.SeekCurrent:
+		target = s.pos + offset
+	case io.SeekEnd:
+		return s.pos, fmt.Errorf("%w: seek relative to end", ErrNotSeekable)
+	default:
+		return s.pos, errors.New("binstruct: invalid whence")
+	}
+
+	if target < s.pos {
+		return s.pos, fmt.Errorf("%w: backward seek from %d to %d", ErrNotSeekable, s.pos, target)
+	}
+
+	skip := target - s.pos
+	if n := int64(len(s.pending)); n > 0 {
+		if skip < n {
+			n = skip
+		}
+		s.pending = s.pending[n:]
+		s.pos += n
+		skip -= n
+	}
+
+	if skip > 0 {
+		n, err := io.CopyN(io.Discard, s.r, skip)
+		s.pos += n
+		if err != nil {
+			return s.pos, err
+		}
+	}
+
+	return s.pos, nil
+}
@@ -0,0 +1,64 @@
+package gocodec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StreamReader(t *testing.T) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.Write([]byte{0x01, 0x00, 0x02, 0xAA, 0xBB, 0x03, 0x04})
+		pw.Close()
+	}()
+
+	var v struct {
+		A uint8
+		B uint16
+		C uint8 `bin:"offset:2"`
+		D uint8 `bin:"offsetStart:6"`
+	}
+
+	r := NewStreamReader(pr, binary.BigEndian)
+
+	b, err := r.Peek(3)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x01, 0x00, 0x02}, b)
+
+	err = r.Unmarshal(&v)
+	require.NoError(t, err)
+	require.Equal(t, uint8(1), v.A)
+	require.Equal(t, uint16(2), v.B)
+	require.Equal(t, uint8(3), v.C)
+	require.Equal(t, uint8(4), v.D)
+}
+
+func Test_StreamReaderBackwardSeek(t *testing.T) {
+	var v struct {
+		A uint8
+		B uint8 `bin:"offsetStart:0"`
+	}
+
+	r := NewStreamReader(iotest.OneByteReader(bytes.NewReader([]byte{0x01, 0x02})), binary.BigEndian)
+	err := r.Unmarshal(&v)
+	require.ErrorIs(t, err, ErrNotSeekable)
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "B", decodeErr.Path)
+}
+
+func Test_StreamReaderOffsetRestore(t *testing.T) {
+	var v struct {
+		Data []byte `bin:"len:2,offsetRestore"`
+	}
+
+	err := NewStreamDecoder(bytes.NewBufferString("ab"), binary.BigEndian).Decode(&v)
+	require.ErrorIs(t, err, ErrNotSeekable)
+	require.EqualError(t, err, `failed set value to field "Data": restore offset: binstruct: seek not supported on stream: backward seek from 2 to 0`)
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -22,6 +23,9 @@ const (
 	tagTypeOrderLE = "le"
 	tagTypeOrderBE = "be"
 
+	tagTypeFloat16  = "f16"
+	tagTypeBFloat16 = "bf16"
+
 	tagTypeLength            = "len"
 	tagTypeOffsetFromCurrent = "offset"
 	tagTypeOffsetFromStart   = "offsetStart"
@@ -106,6 +110,10 @@ func parseTag(t string) ([]tag, error) {
 		case v == tagTypeOrderBE:
 			tags = append(tags, tag{Type: tagTypeOrderBE})
 
+		case v == tagTypeFloat16, v == tagTypeBFloat16, v == tagTypeBCD, v == tagTypeTBCD,
+			v == tagTypeUE, v == tagTypeSE:
+			tags = append(tags, tag{Type: v})
+
 		default:
 			ts := strings.Split(v, ":")
 
@@ -140,10 +148,29 @@ type fieldReadData struct {
 	OffsetRestore bool
 	FuncName      string
 	Order         binary.ByteOrder
+	Fixed         *FixedPoint
+	FloatFormat   string // tagTypeFloat16 or tagTypeBFloat16
+	Time          TimeEncoding
+	DurationUnit  time.Duration
+	TextEncoding  TextEncoding
+	Trim          TextPadding
+	BCD           string // tagTypeBCD or tagTypeTBCD
+	Bits          *int64
+	ExpGolomb     string // tagTypeUE or tagTypeSE
 
 	ElemFieldData *fieldReadData // if type Element
 }
 
+// plain reports whether data only sets the length and byte order,
+// so the encoded size of the value follows from its type.
+func (data *fieldReadData) plain() bool {
+	return !data.Ignore && len(data.Offsets) == 0 && !data.OffsetRestore &&
+		data.FuncName == "" && data.ElemFieldData == nil && data.Fixed == nil &&
+		data.FloatFormat == "" && data.Time == "" && data.DurationUnit == 0 &&
+		data.TextEncoding == "" && data.Trim == PadNone && data.BCD == "" &&
+		data.Bits == nil && data.ExpGolomb == ""
+}
+
 func parseCalc(v string) (nums, ops []string) {
 	cur := v
 	for {
@@ -277,6 +304,35 @@ func parseReadDataFromTags(structValue reflect.Value, tags []tag) (*fieldReadDat
 
 		case tagTypeOrderBE:
 			data.Order = binary.BigEndian
+
+		case tagTypeFloat16, tagTypeBFloat16:
+			data.FloatFormat = t.Type
+
+		case tagTypeFixed, tagTypeFixedUnsigned, tagTypeQ, tagTypeQUnsigned:
+			data.Fixed, err = parseFixedTag(t)
+
+		case tagTypeTime:
+			data.Time, err = parseTimeTag(t.Value)
+
+		case tagTypeDuration:
+			data.DurationUnit, err = parseDurationTag(t.Value)
+
+		case tagTypeBCD, tagTypeTBCD:
+			data.BCD = t.Type
+
+		case tagTypeBits:
+			var n int64
+			n, err = parseValue(structValue, t.Value)
+			data.Bits = &n
+
+		case tagTypeUE, tagTypeSE:
+			data.ExpGolomb = t.Type
+
+		case tagTypeEncoding:
+			data.TextEncoding, err = parseTextEncodingTag(t.Value)
+
+		case tagTypeTrim:
+			data.Trim, err = parseTrimTag(t.Value)
 		}
 
 		if err != nil {
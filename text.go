@@ -0,0 +1,224 @@
+package gocodec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	tagTypeEncoding = "enc"
+	tagTypeTrim     = "trim"
+)
+
+var (
+	// ErrInvalidText is returned when bytes or a string are not valid in
+	// a text encoding.
+	ErrInvalidText = errors.New("binstruct: invalid text")
+
+	// ErrTextTooLong is returned when an encoded string doesn't fit in
+	// its fixed width.
+	ErrTextTooLong = errors.New("binstruct: text too long")
+)
+
+// TextEncoding is the encoding of string fields, set with the `enc` tag.
+// Without a tag string fields hold the raw bytes, which are expected to
+// be UTF-8.
+type TextEncoding string
+
+const (
+	TextUTF8    TextEncoding = "utf8"
+	TextUTF16LE TextEncoding = "utf16le"
+	TextUTF16BE TextEncoding = "utf16be"
+	TextLatin1  TextEncoding = "latin1"
+	TextASCII   TextEncoding = "ascii"
+)
+
+// TextPadding is the padding of fixed-width string fields, set with the
+// `trim` tag. Decoding with PadNUL cuts the string at the first NUL,
+// decoding with PadSpace removes trailing spaces.
+type TextPadding string
+
+const (
+	PadNone  TextPadding = ""
+	PadNUL   TextPadding = "nul"
+	PadSpace TextPadding = "space"
+)
+
+func (e TextEncoding) valid() bool {
+	switch e {
+	case TextUTF8, TextUTF16LE, TextUTF16BE, TextLatin1, TextASCII:
+		return true
+	}
+
+	return false
+}
+
+// Decode converts b from the encoding to a Go string.
+func (e TextEncoding) Decode(b []byte) (string, error) {
+	switch e {
+	case TextUTF8:
+		if !utf8.Valid(b) {
+			return "", fmt.Errorf("%w: invalid utf8", ErrInvalidText)
+		}
+		return string(b), nil
+
+	case TextASCII:
+		for i, c := range b {
+			if c >= utf8.RuneSelf {
+				return "", fmt.Errorf("%w: non ascii byte 0x%02x at %d", ErrInvalidText, c, i)
+			}
+		}
+		return string(b), nil
+
+	case TextLatin1:
+		var sb strings.Builder
+		sb.Grow(len(b))
+		for _, c := range b {
+			sb.WriteRune(rune(c))
+		}
+		return sb.String(), nil
+
+	case TextUTF16LE, TextUTF16BE:
+		if len(b)%2 != 0 {
+			return "", fmt.Errorf("%w: odd length %d for utf16", ErrInvalidText, len(b))
+		}
+
+		units := make([]uint16, len(b)/2)
+		for i := range units {
+			if e == TextUTF16LE {
+				units[i] = GetUint16LE(b[2*i:])
+			} else {
+				units[i] = GetUint16BE(b[2*i:])
+			}
+		}
+
+		var sb strings.Builder
+		for i := 0; i < len(units); i++ {
+			r := rune(units[i])
+			if utf16.IsSurrogate(r) {
+				if i+1 < len(units) {
+					r = utf16.DecodeRune(r, rune(units[i+1]))
+				} else {
+					r = utf8.RuneError
+				}
+				if r == utf8.RuneError {
+					return "", fmt.Errorf("%w: unpaired utf16 surrogate at %d", ErrInvalidText, 2*i)
+				}
+				i++
+			}
+			sb.WriteRune(r)
+		}
+		return sb.String(), nil
+	}
+
+	return "", errors.New(`binstruct: unknown text encoding "` + string(e) + `"`)
+}
+
+// Encode converts the UTF-8 string s to the encoding.
+func (e TextEncoding) Encode(s string) ([]byte, error) {
+	if !utf8.ValidString(s) {
+		return nil, fmt.Errorf("%w: invalid utf8", ErrInvalidText)
+	}
+
+	switch e {
+	case TextUTF8:
+		return []byte(s), nil
+
+	case TextASCII, TextLatin1:
+		max := rune(0x7F)
+		if e == TextLatin1 {
+			max = 0xFF
+		}
+
+		b := make([]byte, 0, len(s))
+		for i, r := range s {
+			if r > max {
+				return nil, fmt.Errorf("%w: rune %q at %d not in %s", ErrInvalidText, r, i, e)
+			}
+			b = append(b, byte(r))
+		}
+		return b, nil
+
+	case TextUTF16LE, TextUTF16BE:
+		units := utf16.Encode([]rune(s))
+		b := make([]byte, 2*len(units))
+		for i, u := range units {
+			if e == TextUTF16LE {
+				PutUint16LE(b[2*i:], u)
+			} else {
+				PutUint16BE(b[2*i:], u)
+			}
+		}
+		return b, nil
+	}
+
+	return nil, errors.New(`binstruct: unknown text encoding "` + string(e) + `"`)
+}
+
+// EncodeFixed encodes s and pads it to n bytes, with NULs or with spaces
+// in the encoding for PadSpace. It returns ErrTextTooLong if s doesn't fit.
+func (e TextEncoding) EncodeFixed(s string, n int, pad TextPadding) ([]byte, error) {
+	b, err := e.Encode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) > n {
+		return nil, fmt.Errorf("%w: %d bytes encoded, %d available", ErrTextTooLong, len(b), n)
+	}
+
+	padChar := "\x00"
+	if pad == PadSpace {
+		padChar = " "
+	}
+
+	padding, err := e.Encode(padChar)
+	if err != nil {
+		return nil, err
+	}
+
+	if (n-len(b))%len(padding) != 0 {
+		return nil, fmt.Errorf("%w: can't pad %d bytes with %s", ErrInvalidText, n-len(b), e)
+	}
+
+	for len(b) < n {
+		b = append(b, padding...)
+	}
+
+	return b, nil
+}
+
+// Trim removes the padding from a decoded string.
+func (p TextPadding) Trim(s string) string {
+	switch p {
+	case PadNUL:
+		if i := strings.IndexByte(s, 0); i >= 0 {
+			return s[:i]
+		}
+	case PadSpace:
+		return strings.TrimRight(s, " ")
+	}
+
+	return s
+}
+
+func parseTextEncodingTag(v string) (TextEncoding, error) {
+	e := TextEncoding(v)
+	if !e.valid() {
+		return "", errors.New(`unknown text encoding "` + v + `"`)
+	}
+
+	return e, nil
+}
+
+func parseTrimTag(v string) (TextPadding, error) {
+	switch p := TextPadding(v); p {
+	case PadNUL, PadSpace:
+		return p, nil
+	}
+
+	return "", errors.New(`unknown trim "` + v + `"`)
+}
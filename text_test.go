@@ -0,0 +1,93 @@
+package gocodec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TextTags(t *testing.T) {
+	data := []byte{
+		'U', 0x00, 'S', 0x00, 'B', 0x00, 0x3D, 0xD8, 0x00, 0xDE, // utf16le "USB😀"
+		0x00, 'h', 0x00, 'i', 0x00, 0x00, 0x00, 0x00, // utf16be "hi" NUL padded
+		'c', 'a', 'f', 0xE9, // latin1 "café"
+		'n', 'a', 'm', 'e', ' ', ' ', // ascii "name" space padded
+		'i', 'd', 0x00, 'x', // NUL terminated, garbage after NUL
+	}
+
+	var v struct {
+		Product string    `bin:"len:10,enc:utf16le"`
+		Short   string    `bin:"len:8,enc:utf16be,trim:nul"`
+		Legacy  string    `bin:"len:4,enc:latin1"`
+		Name    string    `bin:"len:6,enc:ascii,trim:space"`
+		CName   [1]string `bin:"[len:4,trim:nul]"`
+	}
+
+	err := UnmarshalLE(data, &v)
+	require.NoError(t, err)
+	require.Equal(t, "USB😀", v.Product)
+	require.Equal(t, "hi", v.Short)
+	require.Equal(t, "café", v.Legacy)
+	require.Equal(t, "name", v.Name)
+	require.Equal(t, [1]string{"id"}, v.CName)
+}
+
+func Test_TextTagErrors(t *testing.T) {
+	var ascii struct {
+		S string `bin:"len:2,enc:ascii"`
+	}
+	err := UnmarshalLE([]byte{'a', 0x80}, &ascii)
+	require.ErrorIs(t, err, ErrInvalidText)
+
+	var odd struct {
+		S string `bin:"len:3,enc:utf16le"`
+	}
+	err = UnmarshalLE([]byte{'a', 0x00, 'b'}, &odd)
+	require.ErrorIs(t, err, ErrInvalidText)
+
+	var surrogate struct {
+		S string `bin:"len:4,enc:utf16le"`
+	}
+	err = UnmarshalLE([]byte{0x3D, 0xD8, 'a', 0x00}, &surrogate)
+	require.ErrorIs(t, err, ErrInvalidText)
+
+	var unknown struct {
+		S string `bin:"len:2,enc:ebcdic"`
+	}
+	err = UnmarshalLE([]byte{'a', 'b'}, &unknown)
+	require.Error(t, err)
+
+	var notString struct {
+		B []byte `bin:"len:2,trim:nul"`
+	}
+	err = UnmarshalLE([]byte{'a', 'b'}, &notString)
+	require.EqualError(t, err, `failed set value to field "B": enc and trim tags need a string field`)
+}
+
+func Test_TextEncodeFixed(t *testing.T) {
+	b, err := TextUTF16LE.EncodeFixed("hi", 8, PadSpace)
+	require.NoError(t, err)
+	require.Equal(t, []byte{'h', 0, 'i', 0, ' ', 0, ' ', 0}, b)
+
+	b, err = TextLatin1.EncodeFixed("café", 6, PadNUL)
+	require.NoError(t, err)
+	require.Equal(t, []byte{'c', 'a', 'f', 0xE9, 0, 0}, b)
+
+	_, err = TextASCII.EncodeFixed("toolong", 4, PadNUL)
+	require.ErrorIs(t, err, ErrTextTooLong)
+
+	_, err = TextASCII.Encode("café")
+	require.ErrorIs(t, err, ErrInvalidText)
+
+	_, err = TextUTF16BE.EncodeFixed("a", 3, PadNUL)
+	require.ErrorIs(t, err, ErrInvalidText)
+
+	for _, enc := range []TextEncoding{TextUTF8, TextUTF16LE, TextUTF16BE, TextLatin1, TextASCII} {
+		b, err := enc.EncodeFixed("abc", 8, PadSpace)
+		require.NoError(t, err, enc)
+
+		s, err := enc.Decode(b)
+		require.NoError(t, err, enc)
+		require.Equal(t, "abc", PadSpace.Trim(s), enc)
+	}
+}
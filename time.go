@@ -0,0 +1,272 @@
+package gocodec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+const (
+	tagTypeTime     = "time"
+	tagTypeDuration = "duration"
+)
+
+// TimeEncoding is an integer encoding of a point in time, as used by the
+// `time:<encoding>` tag on time.Time fields. All times are decoded in UTC.
+type TimeEncoding string
+
+const (
+	// TimeUnix is signed seconds since 1970-01-01, 4 bytes by default.
+	TimeUnix TimeEncoding = "unix"
+	// TimeUnixMilli is signed milliseconds since 1970-01-01, 8 bytes by default.
+	TimeUnixMilli TimeEncoding = "unixms"
+	// TimeUnixNano is signed nanoseconds since 1970-01-01, 8 bytes by default.
+	TimeUnixNano TimeEncoding = "unixns"
+	// TimeNTP64 is the 8 bytes NTP timestamp: unsigned 32.32 fixed-point
+	// seconds since 1900-01-01 (era 0).
+	TimeNTP64 TimeEncoding = "ntp64"
+	// TimeFileTime is the Windows FILETIME: unsigned 100ns intervals since
+	// 1601-01-01, 8 bytes.
+	TimeFileTime TimeEncoding = "filetime"
+	// TimeDOSDateTime is the 4 bytes MS-DOS date and time, with the date in
+	// the upper 16 bits and 2 seconds resolution. It has no time zone.
+	TimeDOSDateTime TimeEncoding = "dosdatetime"
+	// TimeGPS is unsigned seconds since the GPS epoch 1980-01-06, 4 bytes by
+	// default. The GPS-UTC leap second offset is not applied.
+	TimeGPS TimeEncoding = "gps"
+	// TimeMacHFS is unsigned seconds since 1904-01-01 as used by HFS and
+	// QuickTime, 4 bytes by default.
+	TimeMacHFS TimeEncoding = "mac-hfs"
+)
+
+const (
+	ntpEpochOffset      = 2208988800  // seconds from 1900-01-01 to 1970-01-01
+	fileTimeEpochOffset = 11644473600 // seconds from 1601-01-01 to 1970-01-01
+	gpsEpochOffset      = 315964800   // seconds from 1970-01-01 to 1980-01-06
+	hfsEpochOffset      = 2082844800  // seconds from 1904-01-01 to 1970-01-01
+)
+
+var (
+	// ErrTimeRange is returned when a time can't be represented by an encoding.
+	ErrTimeRange = errors.New("binstruct: time out of range of encoding")
+
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Size returns the default number of bytes of the encoding, or 0 for an
+// unknown encoding.
+func (e TimeEncoding) Size() int {
+	switch e {
+	case TimeUnix, TimeDOSDateTime, TimeGPS, TimeMacHFS:
+		return 4
+	case TimeUnixMilli, TimeUnixNano, TimeNTP64, TimeFileTime:
+		return 8
+	}
+
+	return 0
+}
+
+// Signed reports whether raw values of the encoding are two's complement.
+func (e TimeEncoding) Signed() bool {
+	switch e {
+	case TimeUnix, TimeUnixMilli, TimeUnixNano:
+		return true
+	}
+
+	return false
+}
+
+// fixedWidth reports whether the encoding only exists with its default size.
+func (e TimeEncoding) fixedWidth() bool {
+	switch e {
+	case TimeNTP64, TimeDOSDateTime:
+		return true
+	}
+
+	return false
+}
+
+// Decode converts raw into a time. Raw values of signed encodings are
+// interpreted as int64, so narrower values must be sign extended first.
+func (e TimeEncoding) Decode(raw uint64) (time.Time, error) {
+	var t time.Time
+
+	switch e {
+	case TimeUnix:
+		t = time.Unix(int64(raw), 0)
+	case TimeUnixMilli:
+		t = time.UnixMilli(int64(raw))
+	case TimeUnixNano:
+		t = time.Unix(0, int64(raw))
+	case TimeNTP64:
+		frac := ((raw&0xFFFFFFFF)*1e9 + 1<<31) >> 32
+		t = time.Unix(int64(raw>>32)-ntpEpochOffset, int64(frac))
+	case TimeFileTime:
+		t = time.Unix(int64(raw/1e7)-fileTimeEpochOffset, int64(raw%1e7)*100)
+	case TimeGPS:
+		t = time.Unix(int64(raw)+gpsEpochOffset, 0)
+	case TimeMacHFS:
+		t = time.Unix(int64(raw)-hfsEpochOffset, 0)
+	case TimeDOSDateTime:
+		date, clock := raw>>16&0xFFFF, raw&0xFFFF
+		year, month, day := int(date>>9)+1980, int(date>>5&0x0F), int(date&0x1F)
+		hour, min, sec := int(clock>>11), int(clock>>5&0x3F), int(clock&0x1F)*2
+		if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || min > 59 || sec > 59 {
+			return time.Time{}, fmt.Errorf("binstruct: invalid MS-DOS date time 0x%08x", raw)
+		}
+		t = time.Date(year, time.Month(month), day, hour, min, sec, 0, time.UTC)
+	default:
+		return time.Time{}, errors.New(`binstruct: unknown time encoding "` + string(e) + `"`)
+	}
+
+	return t.UTC(), nil
+}
+
+// Encode converts t into the raw value of the encoding, truncating it to
+// the encoding resolution. Signed values are returned in two's complement.
+func (e TimeEncoding) Encode(t time.Time) (uint64, error) {
+	sec, nsec := t.Unix(), int64(t.Nanosecond())
+
+	rangeErr := func() (uint64, error) {
+		return 0, fmt.Errorf("%w %s: %s", ErrTimeRange, e, t)
+	}
+
+	switch e {
+	case TimeUnix:
+		return uint64(sec), nil
+	case TimeUnixMilli:
+		return uint64(t.UnixMilli()), nil
+	case TimeUnixNano:
+		if t.Before(time.Unix(0, -1<<63)) || t.After(time.Unix(0, 1<<63-1)) {
+			return rangeErr()
+		}
+		return uint64(t.UnixNano()), nil
+	case TimeNTP64:
+		s := sec + ntpEpochOffset
+		if s < 0 || s > 0xFFFFFFFF {
+			return rangeErr()
+		}
+		frac := (uint64(nsec)<<32 + 5e8) / 1e9
+		return uint64(s)<<32 | frac, nil
+	case TimeFileTime:
+		s := sec + fileTimeEpochOffset
+		if s < 0 || uint64(s) > (math.MaxUint64-9999999)/10000000 {
+			return rangeErr()
+		}
+		return uint64(s)*1e7 + uint64(nsec)/100, nil
+	case TimeGPS:
+		s := sec - gpsEpochOffset
+		if s < 0 {
+			return rangeErr()
+		}
+		return uint64(s), nil
+	case TimeMacHFS:
+		s := sec + hfsEpochOffset
+		if s < 0 {
+			return rangeErr()
+		}
+		return uint64(s), nil
+	case TimeDOSDateTime:
+		u := t.UTC()
+		if u.Year() < 1980 || u.Year() > 2107 {
+			return rangeErr()
+		}
+		date := uint64(u.Year()-1980)<<9 | uint64(u.Month())<<5 | uint64(u.Day())
+		clock := uint64(u.Hour())<<11 | uint64(u.Minute())<<5 | uint64(u.Second()/2)
+		return date<<16 | clock, nil
+	}
+
+	return 0, errors.New(`binstruct: unknown time encoding "` + string(e) + `"`)
+}
+
+func parseTimeTag(v string) (TimeEncoding, error) {
+	e := TimeEncoding(v)
+	if e.Size() == 0 {
+		return "", errors.New(`unknown time encoding "` + v + `"`)
+	}
+
+	return e, nil
+}
+
+func parseDurationTag(v string) (time.Duration, error) {
+	switch v {
+	case "ns":
+		return time.Nanosecond, nil
+	case "us":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	case "min":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	}
+
+	return 0, errors.New(`unknown duration unit "` + v + `"`)
+}
+
+func decodeTime(r Reader, fieldValue reflect.Value, fieldData *fieldReadData) error {
+	e := fieldData.Time
+	if fieldValue.Type() != timeType {
+		return errors.New("time tag needs a time.Time field")
+	}
+
+	size := e.Size()
+	if fieldData.Length != nil {
+		if e.fixedWidth() && int(*fieldData.Length) != size {
+			return fmt.Errorf("time encoding %s has a fixed len of %d", e, size)
+		}
+		size = int(*fieldData.Length)
+	}
+
+	var raw uint64
+	var err error
+	if e.Signed() {
+		var i int64
+		i, err = r.ReadIntX(size)
+		raw = uint64(i)
+	} else {
+		raw, err = r.ReadUintX(size)
+	}
+	if err != nil {
+		return err
+	}
+
+	t, err := e.Decode(raw)
+	if err != nil {
+		return err
+	}
+
+	if fieldValue.CanSet() {
+		fieldValue.Set(reflect.ValueOf(t))
+	}
+
+	return nil
+}
+
+func decodeDuration(r Reader, fieldValue reflect.Value, fieldData *fieldReadData) error {
+	if fieldValue.Type() != durationType {
+		return errors.New("duration tag needs a time.Duration field")
+	}
+
+	size := 8
+	if fieldData.Length != nil {
+		size = int(*fieldData.Length)
+	}
+
+	v, err := r.ReadIntX(size)
+	if err != nil {
+		return err
+	}
+
+	if fieldValue.CanSet() {
+		fieldValue.SetInt(v * int64(fieldData.DurationUnit))
+	}
+
+	return nil
+}
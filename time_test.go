@@ -0,0 +1,102 @@
+package gocodec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TimeTags(t *testing.T) {
+	data := []byte{
+		0x65, 0x53, 0xF1, 0x00, // unix 1700000000
+		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFC, 0x18, // unixms -1000
+		0xE8, 0xFE, 0x6F, 0x80, 0x80, 0x00, 0x00, 0x00, // ntp64 2023-11-14 22:13:20.5
+		0x01, 0xDA, 0x17, 0x47, 0xC6, 0x6D, 0x00, 0x05, // filetime 2023-11-14 22:13:20.0000005
+		0x57, 0x6E, 0xB0, 0x8F, // dosdatetime 2023-11-14 22:04:30
+		0x00, 0x00, 0x00, 0x00, // gps epoch
+		0x7C, 0x25, 0xB0, 0x80, // mac-hfs 1970-01-01
+		0x00, 0x00, 0x05, 0xDC, // duration:ms,len:4 1500ms
+	}
+
+	var v struct {
+		Unix     time.Time     `bin:"time:unix"`
+		UnixMs   time.Time     `bin:"time:unixms"`
+		NTP      time.Time     `bin:"time:ntp64"`
+		FileTime time.Time     `bin:"time:filetime"`
+		DOS      time.Time     `bin:"time:dosdatetime"`
+		GPS      time.Time     `bin:"time:gps"`
+		HFS      time.Time     `bin:"time:mac-hfs"`
+		Timeout  time.Duration `bin:"duration:ms,len:4"`
+	}
+
+	err := UnmarshalBE(data, &v)
+	require.NoError(t, err)
+
+	require.Equal(t, time.Unix(1700000000, 0).UTC(), v.Unix)
+	require.Equal(t, time.Unix(-1, 0).UTC(), v.UnixMs)
+	require.Equal(t, time.Unix(1700000000, 500000000).UTC(), v.NTP)
+	require.Equal(t, time.Unix(1700000000, 500).UTC(), v.FileTime)
+	require.Equal(t, time.Date(2023, 11, 14, 22, 4, 30, 0, time.UTC), v.DOS)
+	require.Equal(t, time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC), v.GPS)
+	require.Equal(t, time.Unix(0, 0).UTC(), v.HFS)
+	require.Equal(t, 1500*time.Millisecond, v.Timeout)
+}
+
+func Test_TimeTagErrors(t *testing.T) {
+	var untagged struct {
+		T time.Time
+	}
+	err := UnmarshalBE(make([]byte, 8), &untagged)
+	require.EqualError(t, err, `failed set value to field "T": need set time tag for time.Time`)
+
+	var unknown struct {
+		T time.Time `bin:"time:julian"`
+	}
+	err = UnmarshalBE(make([]byte, 8), &unknown)
+	require.Error(t, err)
+
+	var wrongType struct {
+		T int64 `bin:"time:unix"`
+	}
+	err = UnmarshalBE(make([]byte, 8), &wrongType)
+	require.EqualError(t, err, `failed set value to field "T": time tag needs a time.Time field`)
+
+	var badDOS struct {
+		T time.Time `bin:"time:dosdatetime"`
+	}
+	err = UnmarshalBE([]byte{0x00, 0x00, 0x00, 0x00}, &badDOS)
+	require.Error(t, err)
+}
+
+func Test_TimeEncodingRoundTrip(t *testing.T) {
+	at := time.Date(2024, 2, 29, 13, 37, 42, 123456700, time.UTC)
+
+	tests := []struct {
+		enc  TimeEncoding
+		want time.Time
+	}{
+		{TimeUnix, at.Truncate(time.Second)},
+		{TimeUnixMilli, at.Truncate(time.Millisecond)},
+		{TimeUnixNano, at},
+		{TimeNTP64, at},
+		{TimeFileTime, at},
+		{TimeDOSDateTime, time.Date(2024, 2, 29, 13, 37, 42, 0, time.UTC)},
+		{TimeGPS, at.Truncate(time.Second)},
+		{TimeMacHFS, at.Truncate(time.Second)},
+	}
+
+	for _, tt := range tests {
+		raw, err := tt.enc.Encode(at)
+		require.NoError(t, err, tt.enc)
+
+		got, err := tt.enc.Decode(raw)
+		require.NoError(t, err, tt.enc)
+		require.Equal(t, tt.want, got, tt.enc)
+	}
+
+	_, err := TimeGPS.Encode(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, ErrTimeRange)
+	_, err = TimeDOSDateTime.Encode(time.Date(1979, 12, 31, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, ErrTimeRange)
+}
@@ -0,0 +1,203 @@
+package gocodec
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Tracer receives the reads, seeks and field boundaries of a decode.
+// It replaces printing with the debug flag and can be set with
+// WithTracer or Decoder.SetTracer.
+type Tracer interface {
+	// OnRead is called after a read of want bytes starting at offset.
+	// A want of -1 means reading until EOF.
+	OnRead(offset int64, want int, data []byte, err error)
+	// OnSeek is called after a seek, pos is the resulting position.
+	OnSeek(offset int64, whence int, pos int64, err error)
+	// OnFieldStart is called before decoding the field at path.
+	OnFieldStart(path string, offset int64)
+	// OnFieldEnd is called after decoding the field at path.
+	OnFieldEnd(path string, offset int64, err error)
+}
+
+// WithTracer sets the tracer notified about reads, seeks and fields.
+func WithTracer(t Tracer) Option {
+	return func(o *options) {
+		o.tracer = t
+	}
+}
+
+func whenceString(whence int) string {
+	switch whence {
+	case io.SeekStart:
+		return "SeekStart"
+	case io.SeekCurrent:
+		return "SeekCurrent"
+	case io.SeekEnd:
+		return "SeekEnd"
+	}
+
+	return "invalid"
+}
+
+type hexDumpTracer struct {
+	w io.Writer
+}
+
+// NewHexDumpTracer returns a tracer which writes a hex dump of every read
+// and a line for every seek and field to w. It is the tracer used when
+// the debug flag is set, with w being os.Stdout.
+func NewHexDumpTracer(w io.Writer) Tracer {
+	return &hexDumpTracer{w: w}
+}
+
+func (t *hexDumpTracer) OnRead(offset int64, want int, data []byte, err error) {
+	if want < 0 {
+		fmt.Fprintf(t.w, "ReadAll(): %s", hex.Dump(data))
+		return
+	}
+
+	fmt.Fprintf(t.w, "Read(want: %d|actual: %d): %s", want, len(data), hex.Dump(data))
+}
+
+func (t *hexDumpTracer) OnSeek(offset int64, whence int, pos int64, err error) {
+	fmt.Fprintf(t.w, "Seek(%d, %s) CurPos:%d\n", offset, whenceString(whence), pos)
+}
+
+func (t *hexDumpTracer) OnFieldStart(path string, offset int64) {
+	fmt.Fprintf(t.w, "Field(%s) Start:%d\n", path, offset)
+}
+
+func (t *hexDumpTracer) OnFieldEnd(path string, offset int64, err error) {
+	if err != nil {
+		fmt.Fprintf(t.w, "Field(%s) End:%d Error:%v\n", path, offset, err)
+		return
+	}
+
+	fmt.Fprintf(t.w, "Field(%s) End:%d\n", path, offset)
+}
+
+type slogTracer struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+// NewSlogTracer returns a tracer which logs every event to logger with level.
+func NewSlogTracer(logger *slog.Logger, level slog.Level) Tracer {
+	return &slogTracer{
+		logger: logger,
+		level:  level,
+	}
+}
+
+func (t *slogTracer) log(msg string, err error, attrs ...slog.Attr) {
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	t.logger.LogAttrs(context.Background(), t.level, msg, attrs...)
+}
+
+func (t *slogTracer) OnRead(offset int64, want int, data []byte, err error) {
+	t.log("binstruct read", err,
+		slog.Int64("offset", offset),
+		slog.Int("want", want),
+		slog.Int("actual", len(data)),
+		slog.String("data", hex.EncodeToString(data)),
+	)
+}
+
+func (t *slogTracer) OnSeek(offset int64, whence int, pos int64, err error) {
+	t.log("binstruct seek", err,
+		slog.Int64("offset", offset),
+		slog.String("whence", whenceString(whence)),
+		slog.Int64("pos", pos),
+	)
+}
+
+func (t *slogTracer) OnFieldStart(path string, offset int64) {
+	t.log("binstruct field start", nil,
+		slog.String("path", path),
+		slog.Int64("offset", offset),
+	)
+}
+
+func (t *slogTracer) OnFieldEnd(path string, offset int64, err error) {
+	t.log("binstruct field end", err,
+		slog.String("path", path),
+		slog.Int64("offset", offset),
+	)
+}
+
+// TraceKind is the kind of a recorded TraceEvent.
+type TraceKind string
+
+const (
+	TraceRead       TraceKind = "read"
+	TraceSeek       TraceKind = "seek"
+	TraceFieldStart TraceKind = "fieldStart"
+	TraceFieldEnd   TraceKind = "fieldEnd"
+)
+
+// TraceEvent is an event stored by TraceRecorder. Only the fields
+// relevant to Kind are set.
+type TraceEvent struct {
+	Kind   TraceKind
+	Path   string
+	Offset int64
+	Whence int
+	Want   int
+	Pos    int64
+	Data   []byte
+	Err    error
+}
+
+// TraceRecorder is a Tracer which keeps all events in memory.
+// It is intended for tests.
+type TraceRecorder struct {
+	Events []TraceEvent
+}
+
+func (t *TraceRecorder) OnRead(offset int64, want int, data []byte, err error) {
+	t.Events = append(t.Events, TraceEvent{
+		Kind:   TraceRead,
+		Offset: offset,
+		Want:   want,
+		Data:   append([]byte(nil), data...),
+		Err:    err,
+	})
+}
+
+func (t *TraceRecorder) OnSeek(offset int64, whence int, pos int64, err error) {
+	t.Events = append(t.Events, TraceEvent{
+		Kind:   TraceSeek,
+		Offset: offset,
+		Whence: whence,
+		Pos:    pos,
+		Err:    err,
+	})
+}
+
+func (t *TraceRecorder) OnFieldStart(path string, offset int64) {
+	t.Events = append(t.Events, TraceEvent{
+		Kind:   TraceFieldStart,
+		Path:   path,
+		Offset: offset,
+	})
+}
+
+func (t *TraceRecorder) OnFieldEnd(path string, offset int64, err error) {
+	t.Events = append(t.Events, TraceEvent{
+		Kind:   TraceFieldEnd,
+		Path:   path,
+		Offset: offset,
+		Err:    err,
+	})
+}
+
+// Reset removes all recorded events.
+func (t *TraceRecorder) Reset() {
+	t.Events = nil
+}
@@ -0,0 +1,78 @@
+package gocodec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TraceRecorder(t *testing.T) {
+	var v struct {
+		A uint8
+		B uint16 `bin:"offset:1"`
+	}
+
+	var rec TraceRecorder
+	dec := NewDecoder(bytes.NewReader([]byte{0x01, 0xFF, 0x00, 0x02}), binary.BigEndian)
+	dec.SetTracer(&rec)
+
+	err := dec.Decode(&v)
+	require.NoError(t, err)
+	require.Equal(t, uint16(2), v.B)
+
+	require.Equal(t, []TraceEvent{
+		{Kind: TraceFieldStart, Path: "A", Offset: 0},
+		{Kind: TraceRead, Offset: 0, Want: 1, Data: []byte{0x01}},
+		{Kind: TraceFieldEnd, Path: "A", Offset: 1},
+		{Kind: TraceSeek, Offset: 1, Whence: 1, Pos: 2},
+		{Kind: TraceFieldStart, Path: "B", Offset: 2},
+		{Kind: TraceRead, Offset: 2, Want: 2, Data: []byte{0x00, 0x02}},
+		{Kind: TraceFieldEnd, Path: "B", Offset: 4},
+	}, rec.Events)
+}
+
+func Test_HexDumpTracer(t *testing.T) {
+	var out bytes.Buffer
+	r := NewReaderFromBytes([]byte{0x01, 0x02}, binary.BigEndian, false, WithTracer(NewHexDumpTracer(&out)))
+
+	_, err := r.ReadUint16()
+	require.NoError(t, err)
+	require.Equal(t, "Read(want: 2|actual: 2): 00000000  01 02                                             |..|\n", out.String())
+}
+
+func Test_SlogTracer(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+
+	var v struct {
+		A uint8
+	}
+	err := UnmarshalBE([]byte{0x07}, &v, WithTracer(NewSlogTracer(logger, slog.LevelInfo)))
+	require.NoError(t, err)
+	require.Contains(t, out.String(), `msg="binstruct read" offset=0 want=1 actual=1 data=07`)
+	require.Contains(t, out.String(), `msg="binstruct field end" path=A offset=1`)
+}
+
+func Test_TracePeek(t *testing.T) {
+	data := []byte{0x01, 0x02, 0x03}
+
+	for _, seekable := range []bool{true, false} {
+		var rec TraceRecorder
+		var r Reader
+		if seekable {
+			r = NewReader(bytes.NewReader(data), binary.BigEndian, false, WithTracer(&rec))
+		} else {
+			r = NewStreamReader(bytes.NewReader(data), binary.BigEndian, WithTracer(&rec))
+		}
+
+		b, err := r.Peek(2)
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x01, 0x02}, b)
+
+		require.NotEmpty(t, rec.Events, "seekable %v", seekable)
+		require.Equal(t, TraceEvent{Kind: TraceRead, Offset: 0, Want: 2, Data: []byte{0x01, 0x02}}, rec.Events[0])
+	}
+}
@@ -5,11 +5,25 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
 type unmarshal struct {
-	r Reader
+	r     Reader
+	opts  *options
+	state *decodeState
+}
+
+// decodeState is shared by all readers derived from the same Reader, so
+// nested Unmarshal calls from custom funcs continue the outer decode.
+type decodeState struct {
+	depth     int // Unmarshal calls in progress, > 0 for nested calls
+	nesting   int // structs, slices and arrays entered, for MaxDepth
+	allocated int64
+	path      string
+	node      *FieldLayout
+	bits      *BitReader // current run of bit fields
 }
 
 // An InvalidUnmarshalError describes an invalid argument passed to Unmarshal.
@@ -30,10 +44,36 @@ func (e *InvalidUnmarshalError) Error() string {
 }
 
 func (u *unmarshal) Unmarshal(v interface{}) error {
-	return u.unmarshal(v, nil)
+	if u.state.depth > 0 {
+		return u.unmarshal(v, nil, u.state.path)
+	}
+
+	u.state.allocated = 0
+	u.state.nesting = 0
+	u.state.bits = nil
+
+	var root *FieldLayout
+	if u.opts.layout != nil {
+		root = u.opts.layout
+		*root = FieldLayout{Offset: offset(u.r)}
+		u.state.node = root
+	}
+
+	u.state.depth++
+	defer func() {
+		u.state.depth--
+		u.state.node = nil
+	}()
+
+	err := u.unmarshal(v, nil, "")
+	if root != nil {
+		u.leaveLayout(root, nil, u.r, reflect.Indirect(reflect.ValueOf(v)))
+	}
+
+	return err
 }
 
-func (u *unmarshal) unmarshal(v interface{}, parentStructValues []reflect.Value) error {
+func (u *unmarshal) unmarshal(v interface{}, parentStructValues []reflect.Value, path string) error {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
 		return &InvalidUnmarshalError{reflect.TypeOf(v)}
@@ -45,29 +85,52 @@ func (u *unmarshal) unmarshal(v interface{}, parentStructValues []reflect.Value)
 	valueType := structValue.Type()
 	for i := 0; i < numField; i++ {
 		fieldType := valueType.Field(i)
+		fieldName := fieldPath(path, fieldType.Name)
 		tags, err := parseTag(fieldType.Tag.Get(tagName))
 		if err != nil {
-			return fmt.Errorf(`failed parseTag for field "%s": %w`, fieldType.Name, err)
+			return newDecodeError(fieldName, offset(u.r), fieldType.Type, fmt.Errorf("parse tag: %w", err))
 		}
 
 		fieldData, err := parseReadDataFromTags(structValue, tags)
 		if err != nil {
-			return fmt.Errorf(`failed parse ReadData from tags for field "%s": %w`, fieldType.Name, err)
+			return newDecodeError(fieldName, offset(u.r), fieldType.Type, fmt.Errorf("parse ReadData from tags: %w", err))
 		}
 
 		fieldValue := structValue.Field(i)
-		err = u.setValueToField(structValue, fieldValue, fieldData, parentStructValues)
+		err = u.setValueToField(fieldName, structValue, fieldValue, fieldData, parentStructValues)
 		if err != nil {
-			return fmt.Errorf(`failed set value to field "%s": %w`, fieldType.Name, err)
+			return err
 		}
 	}
 
 	return nil
 }
 
+func fieldPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
+
+// offset returns the current offset of r or -1 if it is unknown.
+func offset(r Reader) int64 {
+	if rr, ok := r.(*reader); ok {
+		return rr.tell()
+	}
+
+	off, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+
+	return off
+}
+
 func (u *unmarshal) setValueToField(
-	structValue, fieldValue reflect.Value, fieldData *fieldReadData, parentStructValues []reflect.Value,
-) error {
+	path string, structValue, fieldValue reflect.Value, fieldData *fieldReadData, parentStructValues []reflect.Value,
+) (err error) {
 	if fieldData == nil {
 		fieldData = &fieldReadData{}
 	}
@@ -76,27 +139,69 @@ func (u *unmarshal) setValueToField(
 		return nil
 	}
 
+	if err = contextErr(u.opts.ctx); err != nil {
+		return newDecodeError(path, offset(u.r), fieldValue.Type(), err)
+	}
+
+	// A field reading whole bytes ends the run of bit fields, the rest
+	// of a partially read byte is skipped. Structs only group fields.
+	if !fieldData.bitField() &&
+		(fieldValue.Kind() != reflect.Struct || fieldValue.Type() == timeType || !fieldData.plain()) {
+		u.state.bits = nil
+	}
+
 	r := u.r
 	if fieldData.Order != nil {
 		r = r.WithOrder(fieldData.Order)
 	}
 
 	if fieldData.OffsetRestore {
-		currentOffset, err := r.Seek(0, io.SeekCurrent)
-		if err != nil {
-			return fmt.Errorf("get current offset: %w", err)
+		currentOffset, seekErr := r.Seek(0, io.SeekCurrent)
+		if seekErr != nil {
+			return newDecodeError(path, -1, fieldValue.Type(), fmt.Errorf("get current offset: %w", seekErr))
 		}
-		defer r.Seek(currentOffset, io.SeekStart)
+		defer func() {
+			_, seekErr := r.Seek(currentOffset, io.SeekStart)
+			if seekErr != nil && err == nil {
+				err = newDecodeError(path, currentOffset, fieldValue.Type(), fmt.Errorf("restore offset: %w", seekErr))
+			}
+		}()
+	}
+
+	err = setOffset(r, fieldData)
+	if err != nil {
+		return newDecodeError(path, offset(r), fieldValue.Type(), fmt.Errorf("set offset: %w", err))
+	}
+
+	start := offset(r)
+	if u.opts.tracer != nil {
+		u.opts.tracer.OnFieldStart(path, start)
+	}
+
+	node, parent := u.enterLayout(path, start)
+	err = u.decodeValue(r, path, structValue, fieldValue, fieldData, parentStructValues)
+	u.leaveLayout(node, parent, r, fieldValue)
+
+	if u.opts.tracer != nil {
+		u.opts.tracer.OnFieldEnd(path, offset(r), err)
 	}
 
-	err := setOffset(r, fieldData)
 	if err != nil {
-		return fmt.Errorf("set offset: %w", err)
+		return newDecodeError(path, start, fieldValue.Type(), err)
 	}
 
+	return nil
+}
+
+func (u *unmarshal) decodeValue(
+	r Reader, path string, structValue, fieldValue reflect.Value, fieldData *fieldReadData, parentStructValues []reflect.Value,
+) error {
 	if fieldData.FuncName != "" {
-		var okCallFunc bool
-		okCallFunc, err = callFunc(r, fieldData.FuncName, structValue, fieldValue)
+		prevPath := u.state.path
+		u.state.path = path
+		defer func() { u.state.path = prevPath }()
+
+		okCallFunc, err := callFunc(r, fieldData.FuncName, structValue, fieldValue)
 		if err != nil {
 			return fmt.Errorf("call custom func(%s): %w", structValue.Type().Name(), err)
 		}
@@ -132,6 +237,38 @@ or
 		return nil
 	}
 
+	if fieldData.Bits != nil || fieldData.ExpGolomb != "" {
+		return u.decodeBits(fieldValue, fieldData)
+	}
+
+	if fieldData.Fixed != nil {
+		return u.decodeFixed(r, fieldValue, fieldData)
+	}
+
+	if fieldData.FloatFormat != "" {
+		return decodeFloat16(r, fieldValue, fieldData.FloatFormat)
+	}
+
+	if fieldData.Time != "" {
+		return decodeTime(r, fieldValue, fieldData)
+	}
+
+	if fieldData.DurationUnit != 0 {
+		return decodeDuration(r, fieldValue, fieldData)
+	}
+
+	if fieldData.BCD != "" {
+		return decodeBCDField(r, fieldValue, fieldData)
+	}
+
+	if fieldValue.Type() == timeType {
+		return errors.New("need set time tag for time.Time")
+	}
+
+	if (fieldData.TextEncoding != "" || fieldData.Trim != PadNone) && fieldValue.Kind() != reflect.String {
+		return errors.New("enc and trim tags need a string field")
+	}
+
 	switch fieldValue.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		var value int64
@@ -237,24 +374,52 @@ or
 			return errors.New("need set tag with len for string")
 		}
 
-		_, b, err := r.ReadBytes(int(*fieldData.Length))
+		strLen := *fieldData.Length
+		if err := checkLimit("MaxStringLen", strLen, u.opts.limits.MaxStringLen); err != nil {
+			return err
+		}
+
+		if err := u.allocate(r, strLen, 1, 1); err != nil {
+			return err
+		}
+
+		_, b, err := u.readBytes(r, int(strLen))
 		if err != nil {
 			return err
 		}
 
+		var str string
+		if fieldData.TextEncoding != "" {
+			str, err = fieldData.TextEncoding.Decode(b)
+			if err != nil {
+				return err
+			}
+		} else {
+			str = u.bytesString(b)
+		}
+
 		if fieldValue.CanSet() {
-			fieldValue.SetString(string(b))
+			fieldValue.SetString(fieldData.Trim.Trim(str))
 		}
 	case reflect.Slice:
 		if fieldData.Length == nil {
 			return errors.New("need set tag with len for slice")
 		}
 
+		if err := checkLimit("MaxSliceLen", *fieldData.Length, u.opts.limits.MaxSliceLen); err != nil {
+			return err
+		}
+
 		arrLen := int(*fieldData.Length)
+		elemType := fieldValue.Type().Elem()
 
-		// If slice of bytes, read bytes and set to slice.
-		if fieldValue.Type().Elem().Kind() == reflect.Uint8 {
-			n, b, err := u.r.ReadBytes(arrLen)
+		// If slice of plain bytes, read bytes and set to slice.
+		if elemType.Kind() == reflect.Uint8 && (fieldData.ElemFieldData == nil || fieldData.ElemFieldData.plain()) {
+			if err := u.allocate(r, *fieldData.Length, 1, 1); err != nil {
+				return err
+			}
+
+			n, b, err := u.readBytes(u.r, arrLen)
 			if err != nil {
 				return err
 			}
@@ -270,12 +435,22 @@ or
 			return nil
 		}
 
+		minSize := fixedSize(elemType, fieldData.ElemFieldData)
+		if err := u.allocate(r, *fieldData.Length, int64(elemType.Size()), minSize); err != nil {
+			return err
+		}
+
 		if fieldValue.CanSet() {
 			// Create slice before populate.
 			fieldValue.Set(reflect.MakeSlice(fieldValue.Type(), arrLen, arrLen))
 		}
 
-		return u.setArrayValueToField(arrLen, structValue, fieldValue, fieldData, parentStructValues)
+		if err := u.enter(); err != nil {
+			return err
+		}
+		defer u.leave()
+
+		return u.setArrayValueToField(arrLen, path, structValue, fieldValue, fieldData, parentStructValues)
 
 	case reflect.Array:
 		arrLen := fieldValue.Len()
@@ -284,13 +459,20 @@ or
 			arrLen = int(*fieldData.Length)
 		}
 
-		return u.setArrayValueToField(arrLen, structValue, fieldValue, fieldData, parentStructValues)
+		if err := u.enter(); err != nil {
+			return err
+		}
+		defer u.leave()
+
+		return u.setArrayValueToField(arrLen, path, structValue, fieldValue, fieldData, parentStructValues)
 
 	case reflect.Struct:
-		err = u.unmarshal(fieldValue.Addr().Interface(), append(parentStructValues, structValue))
-		if err != nil {
-			return fmt.Errorf("unmarshal struct: %w", err)
+		if err := u.enter(); err != nil {
+			return err
 		}
+		defer u.leave()
+
+		return u.unmarshal(fieldValue.Addr().Interface(), append(parentStructValues, structValue), path)
 	default:
 		return errors.New(`type "` + fieldValue.Kind().String() + `" not supported`)
 	}
@@ -299,11 +481,12 @@ or
 }
 
 func (u *unmarshal) setArrayValueToField(
-	arrLen int, structValue, fieldValue reflect.Value, fieldData *fieldReadData, parentStructValues []reflect.Value,
+	arrLen int, path string, structValue, fieldValue reflect.Value, fieldData *fieldReadData, parentStructValues []reflect.Value,
 ) error {
 	for i := 0; i < arrLen; i++ {
 		tmpV := reflect.New(fieldValue.Type().Elem()).Elem()
-		err := u.setValueToField(structValue, tmpV, fieldData.ElemFieldData, parentStructValues)
+		elemPath := path + "[" + strconv.Itoa(i) + "]"
+		err := u.setValueToField(elemPath, structValue, tmpV, fieldData.ElemFieldData, parentStructValues)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,78 @@
+package gocodec
+
+import (
+	"io"
+	"unsafe"
+)
+
+// WithZeroCopy makes []byte fields decoded from in-memory input (Unmarshal
+// and NewReaderFromBytes) alias the input instead of copying it. The
+// decoded slices share memory with the input: changes to the input are
+// visible in the fields and the other way around, and the input can't be
+// garbage collected while a field references it. Each slice has its
+// capacity limited to its length, so appending to it never overwrites
+// the input. Other inputs are not affected by this option.
+func WithZeroCopy() Option {
+	return func(o *options) {
+		o.zeroCopy = true
+	}
+}
+
+// WithZeroCopyStrings is like WithZeroCopy but also makes string fields
+// alias the input with unsafe.String. Go strings are immutable, so the
+// input must not be modified as long as any decoded string is in use.
+func WithZeroCopyStrings() Option {
+	return func(o *options) {
+		o.zeroCopy = true
+		o.zeroCopyStrings = true
+	}
+}
+
+// aliasBytes reads the next n bytes like ReadBytes, but for in-memory
+// input it returns a slice of the input instead of a copy.
+func (r *reader) aliasBytes(n int) (int, []byte, error) {
+	if r.data == nil || n <= 0 {
+		return r.ReadBytes(n)
+	}
+
+	if err := contextErr(r.opts.ctx); err != nil {
+		return 0, nil, err
+	}
+
+	start := r.tell()
+	if start < 0 || start+int64(n) > int64(len(r.data)) {
+		return r.ReadBytes(n)
+	}
+
+	b := r.data[start : start+int64(n) : start+int64(n)]
+	_, err := r.r.Seek(int64(n), io.SeekCurrent)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if r.opts.tracer != nil {
+		r.opts.tracer.OnRead(start, n, b, nil)
+	}
+
+	return n, b, nil
+}
+
+// readBytes reads n bytes for a []byte or string field, aliasing the
+// input when zero-copy decoding is enabled.
+func (u *unmarshal) readBytes(r Reader, n int) (int, []byte, error) {
+	if rr, ok := r.(*reader); ok && u.opts.zeroCopy {
+		return rr.aliasBytes(n)
+	}
+
+	return r.ReadBytes(n)
+}
+
+// bytesString returns b as string, without copying when zero-copy
+// strings are enabled.
+func (u *unmarshal) bytesString(b []byte) string {
+	if u.opts.zeroCopyStrings && len(b) > 0 {
+		return unsafe.String(&b[0], len(b))
+	}
+
+	return string(b)
+}
@@ -0,0 +1,85 @@
+package gocodec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type zeroCopyStruct struct {
+	Size    uint8
+	Payload []byte `bin:"len:Size"`
+	Name    string `bin:"len:3"`
+}
+
+func Test_ZeroCopyBytes(t *testing.T) {
+	data := []byte{0x02, 0xAA, 0xBB, 'f', 'o', 'o', 0xCC}
+
+	var v zeroCopyStruct
+	err := UnmarshalBE(data, &v, WithZeroCopy())
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xAA, 0xBB}, v.Payload)
+	require.Equal(t, "foo", v.Name)
+
+	// The payload aliases the input, the string is still a copy.
+	data[1] = 0x11
+	data[3] = 'g'
+	require.Equal(t, []byte{0x11, 0xBB}, v.Payload)
+	require.Equal(t, "foo", v.Name)
+
+	// Appending must not overwrite the input.
+	require.Equal(t, 2, cap(v.Payload))
+	_ = append(v.Payload, 0xFF)
+	require.Equal(t, byte('g'), data[3])
+}
+
+func Test_ZeroCopyStrings(t *testing.T) {
+	data := []byte{0x01, 0xAA, 'f', 'o', 'o'}
+
+	var v zeroCopyStruct
+	err := UnmarshalBE(data, &v, WithZeroCopyStrings())
+	require.NoError(t, err)
+	require.Equal(t, "foo", v.Name)
+
+	data[2] = 'g'
+	require.Equal(t, "goo", v.Name)
+}
+
+func Test_ZeroCopyWithOrder(t *testing.T) {
+	var v struct {
+		A       uint16 `bin:"le"`
+		Payload []byte `bin:"len:2"`
+	}
+
+	data := []byte{0x01, 0x00, 0xAA, 0xBB}
+	err := UnmarshalBE(data, &v, WithZeroCopy())
+	require.NoError(t, err)
+	require.Equal(t, uint16(1), v.A)
+
+	data[2] = 0x11
+	require.Equal(t, []byte{0x11, 0xBB}, v.Payload)
+}
+
+func Test_ZeroCopyNotInMemory(t *testing.T) {
+	data := []byte{0x01, 0xAA, 'f', 'o', 'o'}
+
+	var v zeroCopyStruct
+	err := NewDecoder(bytes.NewReader(data), binary.BigEndian, WithZeroCopyStrings()).Decode(&v)
+	require.NoError(t, err)
+
+	data[1] = 0x11
+	data[2] = 'g'
+	require.Equal(t, []byte{0xAA}, v.Payload)
+	require.Equal(t, "foo", v.Name)
+}
+
+func Test_ZeroCopyTruncated(t *testing.T) {
+	var v zeroCopyStruct
+	err := UnmarshalBE([]byte{0x01, 0xAA, 'f'}, &v, WithZeroCopy())
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	require.Equal(t, "Name", decodeErr.Path)
+}